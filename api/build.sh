@@ -1 +1,3 @@
-GOOS=linux GOARCH=amd64 go build -o dist/bootstrap .
\ No newline at end of file
+# The image is built on Alpine, so go-fitz has to link the musl build of MuPDF.
+# The ocr tag reads recipe photos with Tesseract, which the image installs.
+GOOS=linux GOARCH=amd64 go build -tags "musl ocr" -o dist/bootstrap .
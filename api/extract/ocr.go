@@ -0,0 +1,102 @@
+package extract
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ErrNotImage is returned for uploads that aren't a picture Tesseract can read
+var ErrNotImage = errors.New("file is not an image")
+
+// ErrOCRUnavailable is returned when the API was built without Tesseract,
+// which needs the ocr build tag and libtesseract installed
+var ErrOCRUnavailable = errors.New("text recognition is not available, the API was built without the ocr tag")
+
+// LowConfidence is the Tesseract confidence, out of 100, below which a line
+// is likely to be misread and should be checked by the user
+const LowConfidence = 70
+
+// OCRLine is a line of text read from an image
+type OCRLine struct {
+	Text       string
+	Confidence float64
+}
+
+// ImageText reads the lines of text in a photo, top to bottom
+func ImageText(data []byte) ([]OCRLine, error) {
+	if !strings.HasPrefix(http.DetectContentType(data), "image/") {
+		return nil, ErrNotImage
+	}
+
+	return recognize(data)
+}
+
+// ocrUnits are the units and sizes that follow an amount, which tell a misread
+// amount from a word such as "V8"
+const ocrUnits = `(?i:c\.|(?:cups?|tbsp|tsp|tablespoons?|teaspoons?|oz|ounces?|lbs?|pounds?|cans?|cloves?|large|medium|small)\b)`
+
+var (
+	// "1 / 2" and "1⁄2"
+	spacedFraction = regexp.MustCompile(`(\d)\s*[/⁄]\s*(\d)`)
+	// ½ and ¼ before a unit are often read as "Y2", "V4" or "/2"
+	misreadFraction = regexp.MustCompile(`(^|\s)(?:[YV]/?|/)([248])(\s+` + ocrUnits + `)`)
+	// a mixed number that lost its space, "11/2" for 1½
+	joinedFraction = regexp.MustCompile(`(^|\s)([1-9])([1-7])/([2-8])(\s|$)`)
+	// "l", "I" or "|" read for a one before a fraction or a unit
+	misreadOne = regexp.MustCompile(`(?i)(^|\s)[lI|!](\s+\d/\d|/\d|\s+` + ocrUnits + `)`)
+	// "O" read for a zero after a digit, "25O g"
+	misreadZero = regexp.MustCompile(`(\d)([oO]+)\b`)
+	// a line broken after a word that can't end one
+	continuedLine = regexp.MustCompile(`(?i)(?:[,(&-]|\b(?:and|or|of|to|with))$`)
+)
+
+// CleanRecipeLines repairs the mistakes OCR usually makes in ingredient
+// lists: it drops specks read as punctuation, joins an ingredient that
+// wrapped onto the next line and fixes fractions. A joined line keeps the
+// lower confidence of the two.
+func CleanRecipeLines(lines []OCRLine) (cleaned []OCRLine) {
+	for _, line := range lines {
+		text := repairFractions(strings.Join(strings.Fields(line.Text), " "))
+		if !strings.ContainsFunc(text, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) {
+			continue
+		}
+
+		if len(cleaned) > 0 {
+			previous := &cleaned[len(cleaned)-1]
+			if continuedLine.MatchString(previous.Text) || strings.Count(previous.Text, "(") > strings.Count(previous.Text, ")") {
+				if strings.HasSuffix(previous.Text, "-") {
+					// "all-" and "purpose flour"
+					previous.Text += text
+				} else {
+					previous.Text += " " + text
+				}
+				previous.Confidence = min(previous.Confidence, line.Confidence)
+				continue
+			}
+		}
+
+		cleaned = append(cleaned, OCRLine{Text: text, Confidence: line.Confidence})
+	}
+	return
+}
+
+func repairFractions(text string) string {
+	text = spacedFraction.ReplaceAllString(text, "$1/$2")
+	text = misreadFraction.ReplaceAllString(text, "${1}1/$2$3")
+	text = joinedFraction.ReplaceAllStringFunc(text, func(match string) string {
+		parts := joinedFraction.FindStringSubmatch(match)
+		// "13/4" is 1¾, but nothing is split off "15/2"
+		if parts[3] >= parts[4] {
+			return match
+		}
+		return parts[1] + parts[2] + " " + parts[3] + "/" + parts[4] + parts[5]
+	})
+	text = misreadOne.ReplaceAllString(text, "${1}1$2")
+	text = misreadZero.ReplaceAllStringFunc(text, func(match string) string {
+		return match[:1] + strings.Repeat("0", len(match)-1)
+	})
+	return text
+}
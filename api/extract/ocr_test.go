@@ -0,0 +1,53 @@
+package extract
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCleanRecipeLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []OCRLine
+		want  []OCRLine
+	}{
+		{
+			name:  "misread halves and quarters",
+			lines: []OCRLine{{"Y2 cup sugar", 90}, {"V4 tsp salt", 90}, {"/2 cup milk", 90}},
+			want:  []OCRLine{{"1/2 cup sugar", 90}, {"1/4 tsp salt", 90}, {"1/2 cup milk", 90}},
+		},
+		{
+			name:  "words that look like fractions",
+			lines: []OCRLine{{"1 can V8 juice", 90}, {"Y2 onion", 90}},
+			want:  []OCRLine{{"1 can V8 juice", 90}, {"Y2 onion", 90}},
+		},
+		{
+			name:  "spaced and joined fractions",
+			lines: []OCRLine{{"1 / 2 cup butter", 90}, {"11/2 cups flour", 90}, {"13/4 cups water", 90}, {"15/2 lb beef", 90}},
+			want:  []OCRLine{{"1/2 cup butter", 90}, {"1 1/2 cups flour", 90}, {"1 3/4 cups water", 90}, {"15/2 lb beef", 90}},
+		},
+		{
+			name:  "misread ones and zeros",
+			lines: []OCRLine{{"l cup rice", 90}, {"I c. oats", 90}, {"| 1/2 tsp cumin", 90}, {"25O g butter", 90}, {"Olive oil", 90}},
+			want:  []OCRLine{{"1 cup rice", 90}, {"1 c. oats", 90}, {"1 1/2 tsp cumin", 90}, {"250 g butter", 90}, {"Olive oil", 90}},
+		},
+		{
+			name:  "specks",
+			lines: []OCRLine{{"2 eggs", 90}, {". ,", 20}, {"-", 30}, {"1 cup milk", 90}},
+			want:  []OCRLine{{"2 eggs", 90}, {"1 cup milk", 90}},
+		},
+		{
+			name:  "wrapped lines",
+			lines: []OCRLine{{"salt and", 90}, {"pepper", 60}, {"2 cups all-", 85}, {"purpose flour", 95}, {"1 onion (finely", 90}, {"chopped)", 80}},
+			want:  []OCRLine{{"salt and pepper", 60}, {"2 cups all-purpose flour", 85}, {"1 onion (finely chopped)", 80}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CleanRecipeLines(tt.lines); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
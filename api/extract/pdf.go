@@ -0,0 +1,42 @@
+// Package extract gets the text out of uploaded documents so that it can go
+// through the same parsing as a pasted recipe
+package extract
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/gen2brain/go-fitz"
+)
+
+// ErrNotPDF is returned for uploads that don't start like a PDF does
+var ErrNotPDF = errors.New("file is not a PDF")
+
+// MaxPDFPages bounds how much of a document is read, a recipe is rarely more
+// than a few pages and cookbook exports can run to hundreds
+const MaxPDFPages = 20
+
+// PDFText returns the text of each page of a PDF, up to MaxPDFPages
+func PDFText(data []byte) ([]string, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\n\r "), []byte("%PDF-")) {
+		return nil, ErrNotPDF
+	}
+
+	doc, err := fitz.NewFromMemory(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	defer doc.Close()
+
+	pages := make([]string, 0, min(doc.NumPage(), MaxPDFPages))
+	for page := 0; page < doc.NumPage() && page < MaxPDFPages; page++ {
+		text, err := doc.Text(page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read page %d of PDF: %w", page+1, err)
+		}
+		pages = append(pages, text)
+	}
+
+	return pages, nil
+}
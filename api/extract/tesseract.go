@@ -0,0 +1,31 @@
+//go:build ocr
+
+package extract
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/otiai10/gosseract/v2"
+)
+
+func recognize(data []byte) ([]OCRLine, error) {
+	client := gosseract.NewClient()
+	defer client.Close()
+
+	if err := client.SetImageFromBytes(data); err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	boxes, err := client.GetBoundingBoxes(gosseract.RIL_TEXTLINE)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recognize text: %w", err)
+	}
+
+	lines := make([]OCRLine, 0, len(boxes))
+	for _, box := range boxes {
+		lines = append(lines, OCRLine{Text: strings.TrimSpace(box.Word), Confidence: box.Confidence})
+	}
+
+	return lines, nil
+}
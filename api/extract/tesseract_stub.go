@@ -0,0 +1,7 @@
+//go:build !ocr
+
+package extract
+
+func recognize(data []byte) ([]OCRLine, error) {
+	return nil, ErrOCRUnavailable
+}
@@ -0,0 +1,134 @@
+package jobs
+
+import (
+	"api/models"
+	"api/providers"
+	"api/proxy"
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+type RecipeImportQueueOptions struct {
+	// Workers is how many recipes are imported at once
+	Workers int
+	// PollInterval is how often idle workers look for jobs they were not
+	// notified about, e.g. ones queued by another replica
+	PollInterval time.Duration
+	// Lease is how long a job can stay Running without being renewed before it
+	// is assumed abandoned, e.g. by a restart, and claimed again. Workers renew
+	// the jobs they run every third of it.
+	Lease time.Duration
+}
+
+// RecipeImportQueue runs the recipe import jobs stored in the database. Jobs
+// are claimed through the store, so several queues, even in different
+// replicas, can share the same jobs.
+type RecipeImportQueue struct {
+	store   proxy.Store
+	options RecipeImportQueueOptions
+	wake    chan struct{}
+}
+
+func NewRecipeImportQueue(store proxy.Store, options RecipeImportQueueOptions) *RecipeImportQueue {
+	return &RecipeImportQueue{store: store, options: options, wake: make(chan struct{})}
+}
+
+// Run works through the queue until ctx is done, picking up any jobs left
+// over from before a restart first
+func (queue *RecipeImportQueue) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for worker := 0; worker < queue.options.Workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			queue.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// Notify wakes an idle worker so a job starts without waiting for the next
+// poll. Busy workers check for more jobs when they finish anyway.
+func (queue *RecipeImportQueue) Notify() {
+	select {
+	case queue.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (queue *RecipeImportQueue) work(ctx context.Context) {
+	ticker := time.NewTicker(queue.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		queue.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-queue.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain runs jobs until there are none left to claim
+func (queue *RecipeImportQueue) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := queue.store.ClaimRecipeImportJob(time.Now().Add(-queue.options.Lease))
+		if errors.Is(err, proxy.ErrNotFound) {
+			return
+		}
+		if err != nil {
+			log.Printf("failed to claim recipe import job: %v", err)
+			return
+		}
+
+		queue.run(*job)
+	}
+}
+
+// run runs the job, renewing its lease until it is done so that a slow recipe
+// site does not make it look abandoned
+func (queue *RecipeImportQueue) run(job models.RecipeImportJob) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		queue.heartbeat(job, done)
+	}()
+
+	_, err := providers.RunRecipeImportJob(queue.store, job)
+	close(done)
+	wg.Wait()
+
+	if err != nil {
+		log.Printf("recipe import job %s failed: %v", job.Id, err)
+	}
+}
+
+func (queue *RecipeImportQueue) heartbeat(job models.RecipeImportJob, done <-chan struct{}) {
+	ticker := time.NewTicker(queue.options.Lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		err := queue.store.RenewRecipeImportJob(job)
+		if errors.Is(err, proxy.ErrLeaseLost) || errors.Is(err, proxy.ErrNotFound) {
+			// The job will fail to finish, so there is no use renewing it
+			return
+		}
+		if err != nil {
+			log.Printf("failed to renew recipe import job %s: %v", job.Id, err)
+		}
+	}
+}
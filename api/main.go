@@ -1,17 +1,28 @@
 package main
 
 import (
+	"api/config"
+	"api/jobs"
+	"api/prices"
+	"api/proxy"
+	"api/proxy/postgres"
+	"api/proxy/sqlite"
 	"api/routes"
+	"context"
+	"fmt"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-var router *gin.Engine
+// maxRecipeImportWorkers bounds how many queued recipes are imported at once
+const maxRecipeImportWorkers = 2
 
-func init() {
-	router = gin.Default()
+func newRouter(handler *routes.Handler) *gin.Engine {
+	router := gin.Default()
 
 	router.Use(CORSMiddleware())
 
@@ -20,22 +31,65 @@ func init() {
 	apiRoutes := router.Group("/api")
 	{
 		// Groceries
-		apiRoutes.GET("/groceries/:householdId", routes.GetGroceries)
-		apiRoutes.PUT("/groceries", routes.CreateGroceryItem)
-		apiRoutes.POST("/groceries", routes.UpdateGroceryItem)
-		apiRoutes.DELETE("/groceries/:householdId/:id", routes.DeleteGroceryItem)
-		apiRoutes.POST("/groceries/batchDelete", routes.BatchDeleteGroceryItems)
-		apiRoutes.POST("/groceries/magic", routes.GroceryMagic)
-		apiRoutes.POST("/tasks/schedule", routes.ScheduleTask)
+		apiRoutes.GET("/groceries/:householdId", handler.GetGroceries)
+		apiRoutes.PUT("/groceries", handler.CreateGroceryItem)
+		apiRoutes.POST("/groceries", handler.UpdateGroceryItem)
+		apiRoutes.PATCH("/groceries/:id", handler.PatchGroceryItem)
+		apiRoutes.DELETE("/groceries/:householdId/:id", handler.DeleteGroceryItem)
+		apiRoutes.POST("/groceries/batchDelete", handler.BatchDeleteGroceryItems)
+		apiRoutes.POST("/groceries/magic", handler.GroceryMagic)
+		apiRoutes.PUT("/groceries/:householdId/layout", handler.SaveLayout)
+		apiRoutes.POST("/groceries/:householdId/layout/move", handler.MoveGroceryItem)
+		apiRoutes.POST("/tasks/schedule", handler.ScheduleTask)
+
+		// Grocery lists
+		apiRoutes.PUT("/lists/:householdId", handler.CreateGroceryList)
+		apiRoutes.PATCH("/lists/:householdId/:listId", handler.RenameGroceryList)
+		apiRoutes.DELETE("/lists/:householdId/:listId", handler.DeleteGroceryList)
+
+		// Recipe imports
+		apiRoutes.POST("/recipes/import", handler.QueueRecipeImport)
+		apiRoutes.GET("/recipes/import/:jobId", handler.GetRecipeImportJob)
+		apiRoutes.POST("/recipes/preview", handler.PreviewRecipeText)
+		apiRoutes.POST("/recipes/preview/pdf", handler.PreviewRecipePDF)
+		apiRoutes.POST("/recipes/preview/image", handler.PreviewRecipeImage)
+		apiRoutes.POST("/recipes/ingredients", handler.AddRecipeIngredients)
+
+		// Recipe library
+		apiRoutes.GET("/recipes/library/:householdId", handler.ListSavedRecipes)
+		apiRoutes.POST("/recipes/library/:householdId", handler.SaveRecipe)
+		apiRoutes.GET("/recipes/library/:householdId/:recipeId", handler.GetSavedRecipe)
+		apiRoutes.PATCH("/recipes/library/:householdId/:recipeId", handler.PatchSavedRecipe)
+		apiRoutes.DELETE("/recipes/library/:householdId/:recipeId", handler.DeleteSavedRecipe)
+		apiRoutes.POST("/recipes/library/:householdId/:recipeId/add", handler.AddSavedRecipe)
+
+		// Prices
+		apiRoutes.GET("/prices", handler.LookupPrices)
+		apiRoutes.POST("/prices/import", handler.ImportPrices)
+		apiRoutes.GET("/prices/status", handler.GetPriceRefreshStatus)
+
+		// Receipts
+		apiRoutes.POST("/receipts/:householdId/scan", handler.ScanReceipt)
+		apiRoutes.POST("/receipts/:householdId/confirm", handler.ConfirmReceipt)
 
 		// Households
-		apiRoutes.PUT("/households", routes.CreateHousehold)
-		apiRoutes.POST("/households/join/:householdId/:userId", routes.JoinHousehold)
-		apiRoutes.POST("/households/leave/:householdId/:userId", routes.LeaveHousehold)
+		apiRoutes.PUT("/households", handler.CreateHousehold)
+		apiRoutes.GET("/households/:householdId", handler.GetHousehold)
+		apiRoutes.PUT("/households/:householdId/measurement-system", handler.SetMeasurementSystem)
+		apiRoutes.POST("/households/join/:householdId/:userId", handler.JoinHousehold)
+		apiRoutes.POST("/households/leave/:householdId/:userId", handler.LeaveHousehold)
 
 		// Users
-		apiRoutes.PUT("/users", routes.CreateUser)
-		apiRoutes.GET("/users/:id", routes.GetUser)
+		apiRoutes.PUT("/users", handler.CreateUser)
+		apiRoutes.GET("/users/:id", handler.GetUser)
+
+		// Admin
+		apiRoutes.GET("/admin/categories/:householdId", handler.GetCategoryOverrides)
+		apiRoutes.PUT("/admin/categories/:householdId", handler.SaveCategoryOverride)
+		apiRoutes.DELETE("/admin/categories/:householdId/:itemName", handler.DeleteCategoryOverride)
+		apiRoutes.GET("/admin/store-rules/:householdId", handler.GetStoreRules)
+		apiRoutes.PUT("/admin/store-rules/:householdId", handler.SaveStoreRule)
+		apiRoutes.DELETE("/admin/store-rules/:householdId/:category", handler.DeleteStoreRule)
 	}
 
 	router.NoRoute(func(c *gin.Context) {
@@ -46,6 +100,8 @@ func init() {
 
 		c.File("../spa/dist/index.html")
 	})
+
+	return router
 }
 
 func CORSMiddleware() gin.HandlerFunc {
@@ -53,7 +109,7 @@ func CORSMiddleware() gin.HandlerFunc {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -65,5 +121,84 @@ func CORSMiddleware() gin.HandlerFunc {
 }
 
 func main() {
+	cfg := config.Load()
+
+	database, err := openStore(cfg)
+	if err != nil {
+		log.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	priceScheduler, err := newPriceScheduler(cfg, database)
+	if err != nil {
+		log.Fatalf("failed to set up price refresh: %v", err)
+	}
+
+	recipeImports := jobs.NewRecipeImportQueue(database, jobs.RecipeImportQueueOptions{
+		Workers:      maxRecipeImportWorkers,
+		PollInterval: 30 * time.Second,
+		Lease:        2 * time.Minute,
+	})
+	go recipeImports.Run(context.Background())
+
+	router := newRouter(routes.NewHandler(database, priceScheduler, recipeImports))
 	router.Run(":57457")
 }
+
+// openStore connects to the configured backend and brings its schema up to date
+func openStore(cfg config.Config) (proxy.Store, error) {
+	switch cfg.DatabaseDriver {
+	case config.SQLite:
+		database, err := sqlite.NewDB(cfg.DatabasePath)
+		if err != nil {
+			return nil, err
+		}
+		if err := database.Migrate(); err != nil {
+			database.Close()
+			return nil, fmt.Errorf("failed to migrate database: %w", err)
+		}
+		return database, nil
+	case config.Postgres:
+		if cfg.DatabaseUrl == "" {
+			return nil, fmt.Errorf("TASKTOTE_DATABASE_URL must be set for the postgres driver")
+		}
+		database, err := postgres.NewDB(cfg.DatabaseUrl)
+		if err != nil {
+			return nil, err
+		}
+		if err := database.Migrate(); err != nil {
+			database.Close()
+			return nil, fmt.Errorf("failed to migrate database: %w", err)
+		}
+		return database, nil
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", cfg.DatabaseDriver)
+	}
+}
+
+// newPriceScheduler registers the configured price sources and starts
+// refreshing the catalog from them in the background
+func newPriceScheduler(cfg config.Config, store proxy.Store) (*prices.Scheduler, error) {
+	var sources []prices.PriceSource
+	if cfg.PriceFixturesDir != "" {
+		fixtureSources, err := prices.FixtureSources(cfg.PriceFixturesDir)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, fixtureSources...)
+	}
+
+	scheduler := prices.NewScheduler(store, sources, prices.SchedulerOptions{
+		Interval:   cfg.PriceRefreshInterval,
+		MaxAge:     cfg.PriceMaxAge,
+		RateLimit:  time.Second,
+		Retries:    3,
+		RetryDelay: 5 * time.Second,
+	})
+
+	if len(sources) > 0 {
+		go scheduler.Run(context.Background())
+	}
+
+	return scheduler, nil
+}
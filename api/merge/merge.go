@@ -0,0 +1,116 @@
+package merge
+
+import (
+	"api/models"
+	"api/parsing"
+	"api/units"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+)
+
+// Ingredient is one line of a recipe that should end up on a grocery list
+type Ingredient struct {
+	Name     string
+	Quantity float64
+	Unit     string
+	// Source is the recipe url the ingredient came from
+	Source string
+}
+
+func FromParsed(ingredient parsing.Ingredient, source string) Ingredient {
+	return Ingredient{
+		Name:     ingredient.Name,
+		Quantity: ingredient.Measure.Amount,
+		Unit:     parsing.NormalizeMeasureName(ingredient.Measure.Name),
+		Source:   source,
+	}
+}
+
+// NormalizeName is used to decide whether two items are the same thing,
+// so "Onions " and "onion" match
+func NormalizeName(name string) string {
+	return inflection.Singular(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// Find returns the index of the item the ingredient should be merged into,
+// or -1 if it needs a new item. Only unchecked groceries are candidates;
+// anything already checked off has been bought.
+func Find(items []models.GroceryItem, ingredient Ingredient) int {
+	name := NormalizeName(ingredient.Name)
+	for i, item := range items {
+		if item.Kind == models.TaskKind || item.Checked {
+			continue
+		}
+		if NormalizeName(item.Name) == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// NewItem builds the grocery item for an ingredient that matched nothing
+func NewItem(householdId string, listId string, ingredient Ingredient) models.GroceryItem {
+	item := models.GroceryItem{
+		HouseholdId: householdId,
+		ListId:      listId,
+		Name:        ingredient.Name,
+		Kind:        models.GroceryKind,
+		Quantity:    ingredient.Quantity,
+		Unit:        ingredient.Unit,
+	}
+	item.Sources = addSource(item.Sources, ingredient.Source)
+	item.GenerateID()
+
+	return item
+}
+
+// Add returns the item with the ingredient's amount added on. The amount is
+// converted into the item's unit where possible, then into any of its extra
+// quantities, and otherwise kept as a new extra quantity.
+func Add(item models.GroceryItem, ingredient Ingredient) models.GroceryItem {
+	item.ExtraQuantities = append([]models.Quantity(nil), item.ExtraQuantities...)
+	item.Sources = addSource(append([]string(nil), item.Sources...), ingredient.Source)
+
+	if ingredient.Quantity == 0 {
+		return item
+	}
+
+	// Items typed in by hand usually have no amount, so the recipe's wins
+	if item.Quantity == 0 {
+		item.Quantity = ingredient.Quantity
+		item.Unit = ingredient.Unit
+		return item
+	}
+
+	name := NormalizeName(item.Name)
+	if amount, ok := units.Convert(name, ingredient.Quantity, ingredient.Unit, item.Unit); ok {
+		item.Quantity = units.Round(item.Quantity+amount, item.Unit)
+		return item
+	}
+
+	for i, extra := range item.ExtraQuantities {
+		if amount, ok := units.Convert(name, ingredient.Quantity, ingredient.Unit, extra.Unit); ok {
+			item.ExtraQuantities[i].Amount = units.Round(extra.Amount+amount, extra.Unit)
+			return item
+		}
+	}
+
+	item.ExtraQuantities = append(item.ExtraQuantities, models.Quantity{Amount: ingredient.Quantity, Unit: ingredient.Unit})
+	return item
+}
+
+func addSource(sources []string, source string) []string {
+	if source == "" {
+		return sources
+	}
+
+	for _, existing := range sources {
+		if existing == source {
+			return sources
+		}
+	}
+
+	return append(sources, source)
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CategoryOverride assigns a household's own category to an item name,
+// taking precedence over data.Categories
+type CategoryOverride struct {
+	HouseholdId string `json:"householdId"`
+	ItemName    string `json:"itemName"`
+	Category    string `json:"category"`
+}
+
+func (override CategoryOverride) Validate() error {
+	if strings.TrimSpace(override.ItemName) == "" {
+		return fmt.Errorf("itemName must not be empty")
+	}
+
+	if strings.TrimSpace(override.Category) == "" {
+		return fmt.Errorf("category must not be empty")
+	}
+
+	return nil
+}
@@ -1,25 +1,90 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
 
 type BatchDeleteGroceryItemsRequest struct {
 	ItemsToDelete []GroceryItem `json:"itemsToDelete"`
 }
 
 type GroceryItemKind string
+
 const (
 	GroceryKind GroceryItemKind = "Grocery"
 	TaskKind    GroceryItemKind = "Task"
 )
 
+func (kind GroceryItemKind) IsValid() bool {
+	switch kind {
+	case GroceryKind, TaskKind:
+		return true
+	}
+
+	return false
+}
+
 type GroceryItem struct {
-	HouseholdId   string          `json:"householdId"`
+	HouseholdId string `json:"householdId"`
+	// ListId is the list the item is on, the household's default list if empty
+	ListId        string          `json:"listId"`
 	Id            string          `json:"id"`
 	Name          string          `json:"name"`
 	Kind          GroceryItemKind `json:"kind"`
 	StoreOverride StorePreference `json:"storeOverride"`
 	Category      string          `json:"category"`
 	Checked       bool            `json:"checked"`
+	// Quantity is 0 when the item has no amount, Unit is empty for counted items
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	// ExtraQuantities holds amounts that could not be converted into Unit,
+	// e.g. "1 can" of tomatoes on top of "400 gram"
+	ExtraQuantities []Quantity `json:"extraQuantities,omitempty"`
+	// Sources are the recipe urls that contributed to this item
+	Sources []string `json:"sources,omitempty"`
+}
+
+type Quantity struct {
+	Amount float64 `json:"quantity"`
+	Unit   string  `json:"unit"`
+}
+
+// GroceryItemPatch is a partial update; nil fields are left unchanged
+type GroceryItemPatch struct {
+	Name            *string          `json:"name"`
+	Kind            *GroceryItemKind `json:"kind"`
+	StoreOverride   *StorePreference `json:"storeOverride"`
+	Category        *string          `json:"category"`
+	Checked         *bool            `json:"checked"`
+	Quantity        *float64         `json:"quantity"`
+	Unit            *string          `json:"unit"`
+	ExtraQuantities *[]Quantity      `json:"extraQuantities"`
+	Sources         *[]string        `json:"sources"`
+	// ListId moves the item to another list of the same household
+	ListId *string `json:"listId"`
+}
+
+func (patch GroceryItemPatch) Validate() error {
+	if patch.Name != nil && strings.TrimSpace(*patch.Name) == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+
+	if patch.Kind != nil && !patch.Kind.IsValid() {
+		return fmt.Errorf("kind must be one of %s or %s, got %q", GroceryKind, TaskKind, *patch.Kind)
+	}
+
+	if patch.Quantity != nil && *patch.Quantity < 0 {
+		return fmt.Errorf("quantity must not be negative")
+	}
+
+	if patch.ListId != nil && *patch.ListId == "" {
+		return fmt.Errorf("listId must not be empty")
+	}
+
+	return nil
 }
 
 type LayoutBlockType string
@@ -34,12 +99,74 @@ type LayoutBlock struct {
 	Type  LayoutBlockType `json:"type"`
 }
 
-type GroceryList struct {
-	Name   string        `json:"name"`
-	Items  []GroceryItem `json:"items"`
+func (blockType LayoutBlockType) IsValid() bool {
+	switch blockType {
+	case Text, GroceryItemId:
+		return true
+	}
+
+	return false
+}
+
+type SaveLayoutRequest struct {
 	Layout []LayoutBlock `json:"layout"`
 }
 
+func (request SaveLayoutRequest) Validate() error {
+	for i, block := range request.Layout {
+		if !block.Type.IsValid() {
+			return fmt.Errorf("layout[%d]: type must be one of %s or %s, got %q", i, Text, GroceryItemId, block.Type)
+		}
+		if block.Type == GroceryItemId && block.Value == "" {
+			return fmt.Errorf("layout[%d]: grocery item id must not be empty", i)
+		}
+	}
+
+	return nil
+}
+
+// MoveGroceryItemRequest moves an item to Index within the section headed by
+// the Text block Section. An empty Section is the unsorted part above the first
+// header, and an Index past the end of the section puts the item last.
+type MoveGroceryItemRequest struct {
+	ItemId  string `json:"itemId"`
+	Section string `json:"section"`
+	Index   int    `json:"index"`
+	// PreferredStores are the household's stores as sent to GroceryMagic, so a
+	// Section named after one of them is remembered as where the item is bought
+	PreferredStores []StorePreference `json:"preferredStores"`
+}
+
+// DefaultGroceryListName is the name of the list every household starts with.
+// The default list shares the household's id, so a household id can be used
+// wherever a list id is expected.
+const DefaultGroceryListName = "Groceries"
+
+type GroceryList struct {
+	Id          string        `json:"id"`
+	HouseholdId string        `json:"householdId"`
+	Name        string        `json:"name"`
+	Items       []GroceryItem `json:"items"`
+	Layout      []LayoutBlock `json:"layout"`
+}
+
+// GroceryListsResponse is returned by GET /groceries/:householdId?lists=all
+type GroceryListsResponse struct {
+	Lists []GroceryList `json:"lists"`
+}
+
+type GroceryListRequest struct {
+	Name string `json:"name"`
+}
+
+func (request GroceryListRequest) Validate() error {
+	if strings.TrimSpace(request.Name) == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+
+	return nil
+}
+
 // Function to generate UUID for ID field
 func (item *GroceryItem) GenerateID() {
 	uuidv7, _ := uuid.NewV7()
@@ -53,3 +180,12 @@ func (item *GroceryItem) GetOrGenerateID() string {
 
 	return item.Id
 }
+
+func (list *GroceryList) GetOrGenerateID() string {
+	if list.Id == "" {
+		uuidv7, _ := uuid.NewV7()
+		list.Id = uuidv7.String()
+	}
+
+	return list.Id
+}
@@ -1,6 +1,31 @@
 package models
 
+import "fmt"
+
 type Household struct {
-	Id   string `json:"householdId"`
-	Name string `json:"name"`
+	Id                string            `json:"householdId"`
+	Name              string            `json:"name"`
+	MeasurementSystem MeasurementSystem `json:"measurementSystem"`
+}
+
+// MeasurementSystem is how a household wants recipe quantities written. The
+// empty system keeps them as the recipe wrote them.
+type MeasurementSystem string
+
+const (
+	MetricSystem   MeasurementSystem = "Metric"
+	ImperialSystem MeasurementSystem = "Imperial"
+)
+
+type MeasurementSystemRequest struct {
+	MeasurementSystem MeasurementSystem `json:"measurementSystem"`
+}
+
+func (request MeasurementSystemRequest) Validate() error {
+	switch request.MeasurementSystem {
+	case "", MetricSystem, ImperialSystem:
+		return nil
+	}
+
+	return fmt.Errorf("measurementSystem must be %s, %s or empty", MetricSystem, ImperialSystem)
 }
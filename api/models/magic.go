@@ -4,9 +4,43 @@ type GroceryMagicRequest struct {
 	HouseholdId     string            `json:"householdId"`
 	GroceryList     GroceryList       `json:"groceryList"`
 	PreferredStores []StorePreference `json:"preferredStores"`
+	// Async queues recipe urls as RecipeImportJobs instead of importing them
+	// before responding
+	Async bool `json:"async"`
+	// Servings scales every recipe to feed this many, 0 keeps them as written
+	Servings float64 `json:"servings"`
 }
 
 type GroceryMagicResponse struct {
 	GroceryList GroceryList        `json:"groceryList"`
 	Schedule    []TaskScheduleItem `json:"schedule"`
+	// RecipeImports has a result for every recipe url on the list
+	RecipeImports []RecipeImportResult `json:"recipeImports"`
+}
+
+type RecipeImportStatus string
+
+const (
+	RecipeQueued        RecipeImportStatus = "Queued"
+	RecipeImported      RecipeImportStatus = "Imported"
+	RecipeNoIngredients RecipeImportStatus = "NoIngredients"
+	RecipeFetchFailed   RecipeImportStatus = "FetchFailed"
+	RecipeParseFailed   RecipeImportStatus = "ParseFailed"
+)
+
+// RecipeImportResult says what happened to one recipe url. Only imported
+// recipes are taken off the list; the others stay so they can be retried.
+type RecipeImportResult struct {
+	Url string `json:"url"`
+	// ItemId is the list item the url was typed into
+	ItemId      string             `json:"itemId"`
+	Status      RecipeImportStatus `json:"status"`
+	Ingredients int                `json:"ingredients"`
+	Error       string             `json:"error,omitempty"`
+	// JobId is set for Queued results, see GET /recipes/import/:jobId
+	JobId string `json:"jobId,omitempty"`
+	// Servings is how many the recipe feeds as written, when it says, and
+	// Scale is what its amounts were multiplied by to feed the servings asked for
+	Servings float64 `json:"servings,omitempty"`
+	Scale    float64 `json:"scale,omitempty"`
 }
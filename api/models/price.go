@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StoreData is the price of an item at one store, as the SPA models it
+type StoreData struct {
+	ItemName    string          `json:"itemName"`
+	Price       float64         `json:"price"`
+	LastUpdated time.Time       `json:"lastUpdated"`
+	StoreName   StorePreference `json:"storeName"`
+}
+
+func (data StoreData) Validate() error {
+	if strings.TrimSpace(data.ItemName) == "" {
+		return fmt.Errorf("itemName must not be empty")
+	}
+
+	if strings.TrimSpace(string(data.StoreName)) == "" {
+		return fmt.Errorf("storeName must not be empty")
+	}
+
+	if data.Price < 0 {
+		return fmt.Errorf("price must not be negative")
+	}
+
+	return nil
+}
+
+// PriceCheck is when the price refresh last asked a store for an item, and
+// whether the store had a price for it
+type PriceCheck struct {
+	ItemName  string          `json:"itemName"`
+	StoreName StorePreference `json:"storeName"`
+	CheckedAt time.Time       `json:"checkedAt"`
+	Found     bool            `json:"found"`
+}
+
+// GroceryItemData is every known price of an item
+type GroceryItemData struct {
+	Name      string      `json:"name"`
+	StoreData []StoreData `json:"storeData"`
+}
+
+type ImportPricesResponse struct {
+	Imported int `json:"imported"`
+}
+
+// PriceRefreshStatus describes the background price refresh
+type PriceRefreshStatus struct {
+	Running      bool      `json:"running"`
+	Sources      []string  `json:"sources"`
+	LastStarted  time.Time `json:"lastStarted"`
+	LastFinished time.Time `json:"lastFinished"`
+	// Counts from the last run. Fresh prices were skipped, Missing items are not
+	// sold by a store, and Failed fetches ran out of retries.
+	Refreshed int `json:"refreshed"`
+	Fresh     int `json:"fresh"`
+	Missing   int `json:"missing"`
+	Failed    int `json:"failed"`
+	// Stale counts the catalog prices that were not updated or checked within
+	// the maximum age by the end of the last run
+	Stale     int    `json:"stale"`
+	LastError string `json:"lastError,omitempty"`
+}
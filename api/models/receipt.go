@@ -0,0 +1,73 @@
+package models
+
+import "fmt"
+
+// ReceiptLine is an item bought, as it is printed on a receipt
+type ReceiptLine struct {
+	Line string `json:"line"`
+	Name string `json:"name"`
+	// Price is what one cost, or a kilo of an item sold by weight
+	Price float64 `json:"price"`
+	// Review is set on lines read from a photo that may have been misread
+	Review bool `json:"review,omitempty"`
+}
+
+// ReceiptMatch is a receipt line that was taken to be an item on the list
+type ReceiptMatch struct {
+	ReceiptLine ReceiptLine `json:"receiptLine"`
+	Item        GroceryItem `json:"item"`
+}
+
+// ReceiptScan is what scanning a receipt did: the items it checked off and
+// the lines it could not find on the household's lists
+type ReceiptScan struct {
+	// Store is where the prices were recorded, Unknown if it wasn't given and
+	// could not be read from the receipt, in which case none were
+	Store     StorePreference `json:"store"`
+	Checked   []ReceiptMatch  `json:"checked"`
+	Unmatched []ReceiptLine   `json:"unmatched"`
+}
+
+// ReceiptItem is an item on the list bought for Price
+type ReceiptItem struct {
+	ItemId string  `json:"itemId"`
+	Price  float64 `json:"price"`
+}
+
+// ConfirmReceiptRequest checks off the items the user matched to the lines a
+// ReceiptScan left unmatched and records their prices
+type ConfirmReceiptRequest struct {
+	HouseholdId string          `json:"householdId"`
+	Store       StorePreference `json:"store"`
+	Items       []ReceiptItem   `json:"items"`
+}
+
+func (request ConfirmReceiptRequest) Validate() error {
+	if request.HouseholdId == "" {
+		return fmt.Errorf("householdId must not be empty")
+	}
+
+	if request.Store == "" || request.Store == Unknown {
+		return fmt.Errorf("store must be given to record prices at")
+	}
+
+	if len(request.Items) == 0 {
+		return fmt.Errorf("items must not be empty")
+	}
+
+	for _, item := range request.Items {
+		if item.ItemId == "" {
+			return fmt.Errorf("itemId must not be empty")
+		}
+
+		if item.Price < 0 {
+			return fmt.Errorf("price of %s must not be negative", item.ItemId)
+		}
+	}
+
+	return nil
+}
+
+type ConfirmReceiptResponse struct {
+	Checked []GroceryItem `json:"checked"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type RecipeImportJobState string
+
+const (
+	JobQueued  RecipeImportJobState = "Queued"
+	JobRunning RecipeImportJobState = "Running"
+	JobDone    RecipeImportJobState = "Done"
+)
+
+// RecipeImportJob imports one recipe url in the background
+type RecipeImportJob struct {
+	Id          string `json:"id"`
+	HouseholdId string `json:"householdId"`
+	ListId      string `json:"listId"`
+	// ItemId is the list item the url was typed into, removed once the
+	// recipe is imported
+	ItemId string `json:"itemId,omitempty"`
+	Url    string `json:"url"`
+	// Servings is what the recipe is scaled to, 0 keeps it as written
+	Servings float64              `json:"servings"`
+	State    RecipeImportJobState `json:"state"`
+	// Status and the fields below it are filled in when the job is Done
+	Status         RecipeImportStatus `json:"status,omitempty"`
+	Ingredients    int                `json:"ingredients"`
+	Error          string             `json:"error,omitempty"`
+	CreatedItemIds []string           `json:"createdItemIds"`
+	UpdatedItemIds []string           `json:"updatedItemIds"`
+	CreatedAt      time.Time          `json:"createdAt"`
+	UpdatedAt      time.Time          `json:"updatedAt"`
+	// LeaseId is set each time the job is claimed. Only the worker holding
+	// the latest lease can renew or finish the job.
+	LeaseId string `json:"-"`
+}
+
+type RecipeImportRequest struct {
+	HouseholdId string `json:"householdId"`
+	// ListId defaults to the household's default list
+	ListId string `json:"listId"`
+	Url    string `json:"url"`
+	ItemId string `json:"itemId"`
+	// Servings scales the recipe to feed this many, 0 keeps it as written
+	Servings float64 `json:"servings"`
+}
+
+func (request RecipeImportRequest) Validate() error {
+	if request.HouseholdId == "" {
+		return fmt.Errorf("householdId must not be empty")
+	}
+
+	if _, err := url.ParseRequestURI(request.Url); err != nil {
+		return fmt.Errorf("url must be a valid url: %w", err)
+	}
+
+	if request.Servings < 0 {
+		return fmt.Errorf("servings must not be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package models
+
+import "fmt"
+
+// RecipeIngredient is one parsed line of a recipe, in the same terms as a
+// grocery item so that it can be corrected before it is added to a list
+type RecipeIngredient struct {
+	Line     string  `json:"line"`
+	Name     string  `json:"name"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	// Review is set on lines read from a photo that may have been misread
+	Review bool `json:"review,omitempty"`
+}
+
+func (ingredient RecipeIngredient) Validate() error {
+	if ingredient.Name == "" {
+		return fmt.Errorf("ingredient name must not be empty")
+	}
+
+	if ingredient.Quantity < 0 {
+		return fmt.Errorf("quantity of %s must not be negative", ingredient.Name)
+	}
+
+	return nil
+}
+
+// RecipePreview is what a recipe would add to a list
+type RecipePreview struct {
+	Name string `json:"name,omitempty"`
+	// Servings is how many the recipe feeds as written, when it says, and
+	// Scale is what its amounts were multiplied by to feed the servings asked for
+	Servings    float64            `json:"servings,omitempty"`
+	Scale       float64            `json:"scale"`
+	Ingredients []RecipeIngredient `json:"ingredients"`
+	// Skipped are the lines that could not be read as ingredients
+	Skipped []string `json:"skipped"`
+}
+
+type RecipeTextRequest struct {
+	Text string `json:"text"`
+	// Servings scales the recipe to feed this many, 0 keeps it as written
+	Servings float64 `json:"servings"`
+}
+
+func (request RecipeTextRequest) Validate() error {
+	if request.Text == "" {
+		return fmt.Errorf("text must not be empty")
+	}
+
+	if request.Servings < 0 {
+		return fmt.Errorf("servings must not be negative")
+	}
+
+	return nil
+}
+
+// AddIngredientsRequest adds the ingredients of a RecipePreview to a list
+type AddIngredientsRequest struct {
+	HouseholdId string `json:"householdId"`
+	// ListId defaults to the household's default list
+	ListId string `json:"listId"`
+	// Source is where the recipe came from, e.g. a url or a file name
+	Source      string             `json:"source"`
+	Ingredients []RecipeIngredient `json:"ingredients"`
+}
+
+func (request AddIngredientsRequest) Validate() error {
+	if request.HouseholdId == "" {
+		return fmt.Errorf("householdId must not be empty")
+	}
+
+	if len(request.Ingredients) == 0 {
+		return fmt.Errorf("ingredients must not be empty")
+	}
+
+	for _, ingredient := range request.Ingredients {
+		if err := ingredient.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type AddIngredientsResponse struct {
+	Created []GroceryItem `json:"created"`
+	Updated []GroceryItem `json:"updated"`
+}
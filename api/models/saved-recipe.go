@@ -0,0 +1,89 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SavedRecipe is a recipe in a household's library. Its ingredients are kept
+// as the user last corrected them, and imports of the same url use them
+// instead of parsing the page again.
+type SavedRecipe struct {
+	Id          string `json:"id"`
+	HouseholdId string `json:"householdId"`
+	Title       string `json:"title"`
+	// SourceUrl is empty for recipes that were pasted or uploaded
+	SourceUrl string `json:"sourceUrl"`
+	// Servings is how many the ingredients feed, 0 if the recipe doesn't say
+	Servings float64 `json:"servings"`
+	// Ingredients each keep the line they were parsed from
+	Ingredients []RecipeIngredient `json:"ingredients"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt"`
+}
+
+func (recipe SavedRecipe) Validate() error {
+	if strings.TrimSpace(recipe.Title) == "" {
+		return fmt.Errorf("title must not be empty")
+	}
+
+	if recipe.Servings < 0 {
+		return fmt.Errorf("servings must not be negative")
+	}
+
+	return validateRecipeIngredients(recipe.Ingredients)
+}
+
+// SavedRecipePatch is a partial update; nil fields are left unchanged
+type SavedRecipePatch struct {
+	Title       *string             `json:"title"`
+	Servings    *float64            `json:"servings"`
+	Ingredients *[]RecipeIngredient `json:"ingredients"`
+}
+
+func (patch SavedRecipePatch) Validate() error {
+	if patch.Title != nil && strings.TrimSpace(*patch.Title) == "" {
+		return fmt.Errorf("title must not be empty")
+	}
+
+	if patch.Servings != nil && *patch.Servings < 0 {
+		return fmt.Errorf("servings must not be negative")
+	}
+
+	if patch.Ingredients != nil {
+		return validateRecipeIngredients(*patch.Ingredients)
+	}
+
+	return nil
+}
+
+func validateRecipeIngredients(ingredients []RecipeIngredient) error {
+	if len(ingredients) == 0 {
+		return fmt.Errorf("ingredients must not be empty")
+	}
+
+	for _, ingredient := range ingredients {
+		if err := ingredient.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddSavedRecipeRequest adds the ingredients of a saved recipe to a list
+type AddSavedRecipeRequest struct {
+	// ListId defaults to the household's default list
+	ListId string `json:"listId"`
+	// Servings scales the recipe to feed this many, 0 keeps it as saved
+	Servings float64 `json:"servings"`
+}
+
+func (request AddSavedRecipeRequest) Validate() error {
+	if request.Servings < 0 {
+		return fmt.Errorf("servings must not be negative")
+	}
+
+	return nil
+}
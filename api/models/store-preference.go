@@ -1,7 +1,39 @@
 package models
 
+import (
+	"fmt"
+	"strings"
+)
+
 type StorePreference string
 
 const (
 	Unknown StorePreference = "unknown"
 )
+
+// StoreRule sends every item of a category to one store
+type StoreRule struct {
+	HouseholdId string          `json:"householdId"`
+	Category    string          `json:"category"`
+	Store       StorePreference `json:"store"`
+}
+
+func (rule StoreRule) Validate() error {
+	if strings.TrimSpace(rule.Category) == "" {
+		return fmt.Errorf("category must not be empty")
+	}
+
+	if strings.TrimSpace(string(rule.Store)) == "" {
+		return fmt.Errorf("store must not be empty")
+	}
+
+	return nil
+}
+
+// StoreChoice counts how often a household has put an item at a store
+type StoreChoice struct {
+	HouseholdId string          `json:"householdId"`
+	ItemName    string          `json:"itemName"`
+	Store       StorePreference `json:"store"`
+	Uses        int             `json:"uses"`
+}
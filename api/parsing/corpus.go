@@ -7390,7 +7390,9 @@ var corpusMeasures = []string{" tablespoon.. ",
 	" teaspoons. ",
 	" teaspoon. ",
 	" teaspoons ",
+	" kilograms ",
 	" teaspoon ",
+	" kilogram ",
 	" canned. ",
 	" ounces. ",
 	" pints.. ",
@@ -7411,6 +7413,8 @@ var corpusMeasures = []string{" tablespoon.. ",
 	" tblsp. ",
 	" tbsp.. ",
 	" tbsps. ",
+	" liters ",
+	" litres ",
 	" can.. ",
 	" cans. ",
 	" cup.. ",
@@ -7427,6 +7431,8 @@ var corpusMeasures = []string{" tablespoon.. ",
 	" tbsp. ",
 	" tbsps ",
 	" tsps. ",
+	" liter ",
+	" litre ",
 	" can. ",
 	" cans ",
 	" cup. ",
@@ -7447,11 +7453,14 @@ var corpusMeasures = []string{" tablespoon.. ",
 	" tbl ",
 	" tbs ",
 	" tsp ",
+	" lbs ",
 	" c. ",
 	" g. ",
 	" ml ",
 	" oz ",
 	" t. ",
+	" lb ",
+	" kg ",
 	" c ",
 	" g ",
 	" t "}
@@ -8369,6 +8378,15 @@ var corpusMeasuresMap = map[string]string{
 	"gram..":       "gram",
 	"grams":        "gram",
 	"grams.":       "gram",
+	"kg":           "kilogram",
+	"kilogram":     "kilogram",
+	"kilograms":    "kilogram",
+	"lb":           "pound",
+	"lbs":          "pound",
+	"liter":        "liter",
+	"liters":       "liter",
+	"litre":        "liter",
+	"litres":       "liter",
 	"milliliter":   "milliliter",
 	"milliliter.":  "milliliter",
 	"ml":           "milliliter",
@@ -8427,199 +8445,3 @@ var corpusMeasuresMap = map[string]string{
 	"tsps":         "tsp",
 	"tsps.":        "tsp",
 }
-
-var densities = map[string]float64{
-	"almond milk":            245.5000000000,
-	"almonds":                115.1000000000,
-	"apple":                  144.6000000000,
-	"apple juice":            243.5000000000,
-	"apples":                 152.2000000000,
-	"applesauce":             247.2000000000,
-	"arugula":                20.0000000000,
-	"asparagus":              204.3000000000,
-	"avocado":                218.0000000000,
-	"bacon":                  156.8000000000,
-	"balsamic vinegar":       255.0000000000,
-	"banana":                 159.1000000000,
-	"bananas":                158.3000000000,
-	"barbecue sauce":         279.2000000000,
-	"basil":                  191.0000000000,
-	"beans":                  189.5000000000,
-	"beef":                   212.8000000000,
-	"beef broth":             225.2000000000,
-	"beef stock":             245.3000000000,
-	"beer":                   237.0000000000,
-	"beets":                  195.8000000000,
-	"black beans":            194.5000000000,
-	"blue cheese":            135.0000000000,
-	"blueberries":            206.5000000000,
-	"bread":                  145.8000000000,
-	"bread crumbs":           114.0000000000,
-	"broccoli":               154.6000000000,
-	"brown rice":             174.2000000000,
-	"brown sugar":            160.9000000000,
-	"butter":                 227.0000000000,
-	"buttermilk":             245.0000000000,
-	"cabbage":                112.1000000000,
-	"canola oil":             180.5000000000,
-	"carrot":                 155.0000000000,
-	"carrots":                178.6000000000,
-	"catsup":                 240.0000000000,
-	"cauliflower":            142.2000000000,
-	"celery":                 182.8000000000,
-	"cheddar cheese":         179.0000000000,
-	"cheese":                 144.7000000000,
-	"cherries":               208.2000000000,
-	"chicken":                194.0000000000,
-	"chicken broth":          203.2000000000,
-	"chicken soup":           248.3000000000,
-	"chicken stock":          240.0000000000,
-	"chickpeas":              193.3000000000,
-	"chile":                  37.0000000000,
-	"chili sauce":            259.0000000000,
-	"chives":                 3.2000000000,
-	"chocolate":              171.2000000000,
-	"cider vinegar":          239.0000000000,
-	"cinnamon":               53.5000000000,
-	"cocoa":                  51.4000000000,
-	"coconut":                261.9000000000,
-	"coconut milk":           236.5000000000,
-	"coconut oil":            218.0000000000,
-	"coffee":                 248.7000000000,
-	"coriander":              16.0000000000,
-	"corn":                   164.1000000000,
-	"corn flour":             123.4000000000,
-	"cornmeal":               144.7000000000,
-	"cornstarch":             128.0000000000,
-	"cottage cheese":         216.3000000000,
-	"cranberries":            123.3000000000,
-	"cream":                  187.8000000000,
-	"cream cheese":           237.3000000000,
-	"cream of mushroom soup": 249.9000000000,
-	"cucumber":               146.3000000000,
-	"dates":                  147.0000000000,
-	"dill weed":              8.9000000000,
-	"dressing":               245.4000000000,
-	"egg noodles":            114.2000000000,
-	"eggplant":               104.0000000000,
-	"evaporated milk":        252.0000000000,
-	"fennel":                 87.0000000000,
-	"feta cheese":            150.0000000000,
-	"flour":                  113.7000000000,
-	"fruit":                  225.8000000000,
-	"garlic":                 144.9000000000,
-	"gelatin":                195.0000000000,
-	"ginger":                 96.0000000000,
-	"graham crackers":        84.0000000000,
-	"green beans":            220.7000000000,
-	"green chilies":          241.0000000000,
-	"green onions":           71.0000000000,
-	"green pepper":           130.0000000000,
-	"green peppers":          238.5000000000,
-	"ham":                    250.4000000000,
-	"hamburger":              244.0000000000,
-	"hazelnuts":              108.3000000000,
-	"honey":                  59.1000000000,
-	"ice":                    187.3000000000,
-	"kale":                   117.0000000000,
-	"kidney beans":           194.6000000000,
-	"leeks":                  75.0000000000,
-	"lemon":                  196.9000000000,
-	"lemon juice":            244.0000000000,
-	"lemons":                 212.0000000000,
-	"lettuce":                49.2000000000,
-	"lime":                   198.0000000000,
-	"lime juice":             244.0000000000,
-	"mango":                  251.0000000000,
-	"maple syrup":            322.0000000000,
-	"margarine":              225.7000000000,
-	"marshmallows":           39.3000000000,
-	"mayonnaise":             230.6000000000,
-	"milk":                   220.3000000000,
-	"molasses":               337.0000000000,
-	"mushrooms":              116.8000000000,
-	"mustard":                153.0000000000,
-	"nuts":                   150.6000000000,
-	"oatmeal":                60.5000000000,
-	"oats":                   81.0000000000,
-	"oil":                    207.3000000000,
-	"olive oil":              216.0000000000,
-	"onion":                  152.6000000000,
-	"onions":                 176.5000000000,
-	"orange":                 237.7000000000,
-	"orange juice":           251.2000000000,
-	"oranges":                176.0000000000,
-	"parmesan":               126.7000000000,
-	"parmesan cheese":        100.0000000000,
-	"parsley":                32.8000000000,
-	"pasta":                  116.0000000000,
-	"peaches":                229.9000000000,
-	"peanut butter":          154.4000000000,
-	"peanut oil":             216.0000000000,
-	"peanuts":                135.8000000000,
-	"pears":                  190.6000000000,
-	"peas":                   171.1000000000,
-	"pecans":                 107.0000000000,
-	"pepper":                 124.0000000000,
-	"pie crust":              129.0000000000,
-	"pine nuts":              135.0000000000,
-	"pineapple":              235.3000000000,
-	"pineapple juice":        250.0000000000,
-	"pork":                   146.7000000000,
-	"potato":                 191.7000000000,
-	"potatoes":               180.4000000000,
-	"pumpkin":                134.1000000000,
-	"quinoa":                 177.5000000000,
-	"raisins":                140.2000000000,
-	"raspberries":            179.8000000000,
-	"red pepper":             125.0000000000,
-	"red wine vinegar":       239.0000000000,
-	"rice":                   89.2000000000,
-	"ricotta cheese":         247.0000000000,
-	"salad":                  207.0000000000,
-	"salad oil":              214.0000000000,
-	"salmon":                 177.0000000000,
-	"salsa":                  250.0000000000,
-	"salt":                   292.0000000000,
-	"sauce":                  251.4000000000,
-	"sausage":                140.3000000000,
-	"scallions":              100.0000000000,
-	"semolina":               107.6000000000,
-	"sesame oil":             218.0000000000,
-	"sesame seeds":           144.0000000000,
-	"shallots":               14.4000000000,
-	"shortening":             207.3000000000,
-	"shrimp":                 200.2000000000,
-	"skim milk":              245.0000000000,
-	"soda":                   57.5000000000,
-	"sour cream":             237.2000000000,
-	"soy sauce":              243.5000000000,
-	"spaghetti":              151.4000000000,
-	"spinach":                164.1000000000,
-	"spray":                  247.0000000000,
-	"sugar":                  205.8000000000,
-	"sweet potatoes":         224.0000000000,
-	"swiss cheese":           137.7000000000,
-	"tofu":                   250.0000000000,
-	"tomato":                 230.5000000000,
-	"tomato juice":           241.2000000000,
-	"tomato sauce":           217.1000000000,
-	"tomato soup":            206.2000000000,
-	"tomatoes":               177.6000000000,
-	"tuna":                   146.0000000000,
-	"turkey":                 206.6000000000,
-	"vanilla":                175.9000000000,
-	"vegetable broth":        225.3000000000,
-	"vegetable oil":          211.5000000000,
-	"vegetable shortening":   205.0000000000,
-	"vinegar":                238.0000000000,
-	"walnuts":                95.0000000000,
-	"water":                  243.2000000000,
-	"whipped cream":          70.0000000000,
-	"white bread":            41.2000000000,
-	"white rice":             172.9000000000,
-	"worcestershire sauce":   275.0000000000,
-	"yellow cornmeal":        143.3000000000,
-	"yogurt":                 211.8000000000,
-	"zucchini":               194.4000000000,
-}
@@ -1,6 +1,8 @@
 package parsing
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"regexp"
@@ -16,18 +18,25 @@ func NewFromHTML(name, htmlstring string) (r *Recipe, err error) {
 	return
 }
 
+// ErrFetch is wrapped by NewFromURL when the page could not be downloaded, as
+// opposed to downloaded but not parsed
+var ErrFetch = errors.New("failed to fetch recipe")
+
 func NewFromURL(url string) (r *Recipe, err error) {
 	client := http.Client{
 		Timeout: 10 * time.Second,
 	}
 	resp, err := client.Get(url)
 	if err != nil {
-		return
+		return nil, fmt.Errorf("%w: %v", ErrFetch, err)
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%w: %s returned %s", ErrFetch, url, resp.Status)
+	}
 	html, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return
+		return nil, fmt.Errorf("%w: %v", ErrFetch, err)
 	}
 
 	return NewFromHTML(url, string(html))
@@ -70,6 +79,17 @@ func SanitizeLine(s string) string {
 	return s
 }
 
+// NormalizeMeasureName maps a measure as it was written ("Tbsp.", "cups") to
+// its canonical unit ("tbl", "cup"). Counted ingredients ("whole") have no unit.
+func NormalizeMeasureName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if unit, ok := corpusMeasuresMap[name]; ok {
+		return unit
+	}
+
+	return ""
+}
+
 // IngredientList will return a string containing the ingredient list
 func (r *Recipe) IngredientList() (ingredientList IngredientList) {
 	ingredientList = IngredientList{make([]Ingredient, len(r.Lines))}
@@ -0,0 +1,23 @@
+package parsing
+
+import (
+	"strings"
+	"unicode"
+)
+
+func removeEmojis(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// ParseItemName turns what was typed into the list, e.g. "🥛 Milk ", into the
+// form used for lookups, "milk"
+func ParseItemName(itemName string) string {
+	parsedItemName := removeEmojis(itemName)
+	parsedItemName = strings.TrimSpace(parsedItemName)
+	return strings.ToLower(parsedItemName)
+}
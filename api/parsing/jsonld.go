@@ -0,0 +1,190 @@
+package parsing
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// recipeData is what a page says about its recipe in structured markup
+type recipeData struct {
+	Name        string
+	Yield       string
+	TotalTime   string
+	Image       string
+	Ingredients []string
+}
+
+// findJSONLDRecipe returns the first schema.org Recipe with ingredients in the
+// page's application/ld+json scripts
+func findJSONLDRecipe(doc *html.Node) (recipe recipeData, ok bool) {
+	var f func(n *html.Node) bool
+	f = func(n *html.Node) bool {
+		if n.DataAtom == atom.Script && isJSONLDScript(n) && n.FirstChild != nil {
+			var data interface{}
+			if err := json.Unmarshal([]byte(n.FirstChild.Data), &data); err == nil {
+				if recipe, ok = findRecipeObject(data); ok {
+					return true
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if f(c) {
+				return true
+			}
+		}
+		return false
+	}
+	f(doc)
+	return
+}
+
+func isJSONLDScript(n *html.Node) bool {
+	return strings.EqualFold(strings.TrimSpace(attribute(n, "type")), "application/ld+json")
+}
+
+// findRecipeObject looks for a Recipe in a JSON-LD document, which can be a
+// single object, an array of them or an object with a @graph, and sometimes
+// nests the recipe under another node such as a WebPage's mainEntity
+func findRecipeObject(data interface{}) (recipe recipeData, ok bool) {
+	switch v := data.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if recipe, ok = findRecipeObject(item); ok {
+				return
+			}
+		}
+	case map[string]interface{}:
+		if isRecipeType(v["@type"]) {
+			recipe = recipeDataFromJSONLD(v)
+			if len(recipe.Ingredients) > 0 {
+				return recipe, true
+			}
+		}
+		if recipe, ok = findRecipeObject(v["@graph"]); ok {
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			if key != "@graph" {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if recipe, ok = findRecipeObject(v[key]); ok {
+				return
+			}
+		}
+	}
+	return
+}
+
+// isRecipeType matches "Recipe", "schema:Recipe" and lists like
+// ["Recipe", "NewsArticle"]
+func isRecipeType(typ interface{}) bool {
+	switch v := typ.(type) {
+	case string:
+		v = v[strings.LastIndexAny(v, ":/")+1:]
+		return strings.EqualFold(v, "Recipe")
+	case []interface{}:
+		for _, t := range v {
+			if isRecipeType(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func recipeDataFromJSONLD(object map[string]interface{}) (recipe recipeData) {
+	recipe.Name = jsonLDText(object["name"])
+	recipe.Yield = jsonLDYield(object["recipeYield"])
+	recipe.TotalTime = jsonLDText(object["totalTime"])
+	recipe.Image = jsonLDImage(object["image"])
+
+	ingredients := object["recipeIngredient"]
+	if ingredients == nil {
+		// the property was called ingredients before schema.org renamed it
+		ingredients = object["ingredients"]
+	}
+	switch v := ingredients.(type) {
+	case string:
+		recipe.Ingredients = appendIngredientLine(recipe.Ingredients, v)
+	case []interface{}:
+		for _, line := range v {
+			recipe.Ingredients = appendIngredientLine(recipe.Ingredients, jsonLDText(line))
+		}
+	}
+	return
+}
+
+// appendIngredientLine cleans up a line as it is written in markup, where
+// entities are often escaped twice and whitespace left in from templates
+func appendIngredientLine(lines []string, line string) []string {
+	line = strings.Join(strings.Fields(html.UnescapeString(line)), " ")
+	if line == "" {
+		return lines
+	}
+	return append(lines, line)
+}
+
+func jsonLDText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(html.UnescapeString(v))
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		if len(v) > 0 {
+			return jsonLDText(v[0])
+		}
+	case map[string]interface{}:
+		if text := jsonLDText(v["@value"]); text != "" {
+			return text
+		}
+		return jsonLDText(v["name"])
+	}
+	return ""
+}
+
+// jsonLDYield picks the most descriptive of the yields a page gives, which
+// are often a bare number alongside e.g. "4 servings"
+func jsonLDYield(value interface{}) string {
+	yields, ok := value.([]interface{})
+	if !ok {
+		return jsonLDText(value)
+	}
+
+	var best string
+	for _, yield := range yields {
+		if text := jsonLDText(yield); len(text) > len(best) {
+			best = text
+		}
+	}
+	return best
+}
+
+// jsonLDImage reads a url, an ImageObject or a list of either
+func jsonLDImage(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []interface{}:
+		for _, image := range v {
+			if url := jsonLDImage(image); url != "" {
+				return url
+			}
+		}
+	case map[string]interface{}:
+		if url := jsonLDImage(v["url"]); url != "" {
+			return url
+		}
+		return jsonLDImage(v["contentUrl"])
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package parsing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// wantIngredient is an ingredient as a test expects it parsed
+type wantIngredient struct {
+	name   string
+	amount float64
+	unit   string
+}
+
+// wantRecipe is what a test expects of a recipe page
+type wantRecipe struct {
+	name        string
+	yield       string
+	totalTime   string
+	image       string
+	ingredients []wantIngredient
+}
+
+func parseFixture(t *testing.T, path string) *Recipe {
+	t.Helper()
+
+	page, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recipe, err := NewFromHTML("https://example.com/"+filepath.Base(path), string(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return recipe
+}
+
+func checkRecipe(t *testing.T, recipe *Recipe, want wantRecipe) {
+	t.Helper()
+
+	if recipe.Name != want.name {
+		t.Errorf("name: got %q, want %q", recipe.Name, want.name)
+	}
+	if recipe.Yield != want.yield {
+		t.Errorf("yield: got %q, want %q", recipe.Yield, want.yield)
+	}
+	if recipe.TotalTime != want.totalTime {
+		t.Errorf("totalTime: got %q, want %q", recipe.TotalTime, want.totalTime)
+	}
+	if recipe.Image != want.image {
+		t.Errorf("image: got %q, want %q", recipe.Image, want.image)
+	}
+
+	ingredients := recipe.IngredientList().Ingredients
+	if len(ingredients) != len(want.ingredients) {
+		t.Errorf("got %d ingredients, want %d, skipped %q", len(ingredients), len(want.ingredients), recipe.Skipped)
+	}
+	for i := 0; i < len(ingredients) && i < len(want.ingredients); i++ {
+		got := ingredients[i]
+		if got.Name != want.ingredients[i].name || got.Measure.Amount != want.ingredients[i].amount || got.Measure.Name != want.ingredients[i].unit {
+			t.Errorf("ingredient %d: got %s %v %s from %q, want %+v", i, got.Name, got.Measure.Amount, got.Measure.Name, got.Line, want.ingredients[i])
+		}
+	}
+}
+
+func TestJSONLD(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    wantRecipe
+	}{
+		{
+			fixture: "single.html",
+			want: wantRecipe{
+				name:      "Carrot Soup",
+				yield:     "4 servings",
+				totalTime: "PT45M",
+				image:     "https://example.com/images/carrot-soup.jpg",
+				ingredients: []wantIngredient{
+					{"onion", 2, "whole"},
+					{"chicken broth", 1, "cup"},
+					{"carrot", 200, "g"},
+					// structured data says it is an ingredient, so it is kept
+					// without an amount
+					{"salt", 0, "whole"},
+				},
+			},
+		},
+		{
+			fixture: "graph.html",
+			want: wantRecipe{
+				name:      "Banana Bread",
+				yield:     "1 loaf",
+				totalTime: "PT1H15M",
+				image:     "https://example.com/images/banana-bread.jpg",
+				ingredients: []wantIngredient{
+					{"banana", 3, "whole"},
+					{"flour", 2, "cups"},
+					{"sugar", 0.5, "cup"},
+					{"baking soda", 1, "teaspoon"},
+				},
+			},
+		},
+		{
+			fixture: "array-type.html",
+			want: wantRecipe{
+				name:      "Weeknight Chili",
+				yield:     "6",
+				totalTime: "PT1H",
+				image:     "https://example.com/images/chili-16x9.jpg",
+				ingredients: []wantIngredient{
+					{"beef", 1, "lb"},
+					{"kidney bean", 2, "cans"},
+					{"onion", 1, "whole"},
+					{"chili powder", 2, "tablespoons"},
+				},
+			},
+		},
+		{
+			fixture: "main-entity.html",
+			want: wantRecipe{
+				name:      "Pancakes",
+				yield:     "8 pancakes",
+				totalTime: "PT20M",
+				image:     "https://example.com/images/pancakes.jpg",
+				ingredients: []wantIngredient{
+					{"flour", 1, "cup"},
+					{"egg", 2, "whole"},
+					{"milk", 1.25, "cups"},
+					{"butter", 2, "tablespoons"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			checkRecipe(t, parseFixture(t, filepath.Join("testdata", "jsonld", tt.fixture)), tt.want)
+		})
+	}
+}
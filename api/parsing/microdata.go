@@ -0,0 +1,156 @@
+package parsing
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// findMarkupRecipe reads a recipe marked up with microdata (itemprop) or RDFa
+// (property) attributes, including the older data-vocabulary.org
+// "ingredient", "yield" and "photo". Properties are read from inside the
+// element typed as a Recipe, or from the whole page when a blog marks its
+// ingredients without declaring the recipe around them.
+func findMarkupRecipe(doc *html.Node) (recipe recipeData, ok bool) {
+	root := findMarkupRecipeRoot(doc)
+	if root == nil {
+		root = doc
+	}
+
+	var f func(n *html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			for _, property := range markupProperties(n) {
+				switch property {
+				case "recipeIngredient", "ingredients", "ingredient":
+					recipe.Ingredients = appendIngredientLine(recipe.Ingredients, markupValue(n))
+				case "name":
+					if recipe.Name == "" {
+						recipe.Name = markupValue(n)
+					}
+				case "recipeYield", "yield":
+					if recipe.Yield == "" {
+						recipe.Yield = markupValue(n)
+					}
+				case "totalTime":
+					if recipe.TotalTime == "" {
+						recipe.TotalTime = markupValue(n)
+					}
+				case "image", "photo":
+					if recipe.Image == "" {
+						recipe.Image = markupValue(n)
+					}
+				}
+			}
+
+			// The properties of a nested item, e.g. the author's name, are not
+			// the recipe's
+			if n != root && isMarkupItem(n) {
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(root)
+
+	return recipe, len(recipe.Ingredients) > 0
+}
+
+func findMarkupRecipeRoot(n *html.Node) *html.Node {
+	if n.Type == html.ElementNode {
+		for _, typ := range append(strings.Fields(attribute(n, "itemtype")), strings.Fields(attribute(n, "typeof"))...) {
+			if isRecipeType(typ) {
+				return n
+			}
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if root := findMarkupRecipeRoot(c); root != nil {
+			return root
+		}
+	}
+	return nil
+}
+
+func isMarkupItem(n *html.Node) bool {
+	_, itemscope := attributeValue(n, "itemscope")
+	_, typeof := attributeValue(n, "typeof")
+	return itemscope || typeof
+}
+
+// markupProperties lists the itemprop and property names of an element
+// without their vocabulary, so "schema:recipeIngredient" and
+// "http://schema.org/recipeIngredient" are both "recipeIngredient"
+func markupProperties(n *html.Node) (properties []string) {
+	for _, property := range append(strings.Fields(attribute(n, "itemprop")), strings.Fields(attribute(n, "property"))...) {
+		properties = append(properties, property[strings.LastIndexAny(property, ":/")+1:])
+	}
+	return
+}
+
+// markupValue reads a property the way microdata defines it: from an
+// attribute for elements such as meta, img and time, otherwise from the text
+func markupValue(n *html.Node) string {
+	if content, ok := attributeValue(n, "content"); ok {
+		return strings.TrimSpace(content)
+	}
+
+	switch n.DataAtom {
+	case atom.Img, atom.Audio, atom.Video, atom.Source, atom.Embed, atom.Iframe:
+		return strings.TrimSpace(attribute(n, "src"))
+	case atom.A, atom.Link, atom.Area:
+		return strings.TrimSpace(attribute(n, "href"))
+	case atom.Time:
+		if datetime, ok := attributeValue(n, "datetime"); ok {
+			return strings.TrimSpace(datetime)
+		}
+	case atom.Data, atom.Meter:
+		return strings.TrimSpace(attribute(n, "value"))
+	}
+
+	return strings.Join(strings.Fields(textContent(n)), " ")
+}
+
+// inlineElements run on in text, anything else, like a list item or a
+// paragraph, is kept apart from its neighbours
+var inlineElements = map[atom.Atom]bool{
+	atom.A: true, atom.Abbr: true, atom.B: true, atom.Code: true, atom.Data: true, atom.Em: true,
+	atom.I: true, atom.Mark: true, atom.S: true, atom.Small: true, atom.Span: true,
+	atom.Strong: true, atom.Sub: true, atom.Sup: true, atom.Time: true, atom.U: true,
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+
+	var text strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.DataAtom == atom.Script || c.DataAtom == atom.Style {
+			continue
+		}
+		if c.Type == html.ElementNode && !inlineElements[c.DataAtom] {
+			text.WriteString(" " + textContent(c) + " ")
+			continue
+		}
+		text.WriteString(textContent(c))
+	}
+	return text.String()
+}
+
+func attribute(n *html.Node, key string) string {
+	value, _ := attributeValue(n, key)
+	return value
+}
+
+func attributeValue(n *html.Node, key string) (string, bool) {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
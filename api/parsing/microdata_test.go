@@ -0,0 +1,84 @@
+package parsing
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the microdata tests")
+
+// goldenRecipe is what a golden file records of a parsed recipe page
+type goldenRecipe struct {
+	Name        string             `json:"name"`
+	Yield       string             `json:"yield"`
+	TotalTime   string             `json:"totalTime"`
+	Image       string             `json:"image"`
+	Ingredients []goldenIngredient `json:"ingredients"`
+	Skipped     []string           `json:"skipped,omitempty"`
+}
+
+type goldenIngredient struct {
+	Line   string  `json:"line"`
+	Name   string  `json:"name"`
+	Amount float64 `json:"amount"`
+	Unit   string  `json:"unit"`
+}
+
+// TestMicrodata parses each page in testdata/microdata and compares the
+// recipe with the page's .golden file. Run with -update to rewrite them.
+func TestMicrodata(t *testing.T) {
+	pages, err := filepath.Glob(filepath.Join("testdata", "microdata", "*.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) == 0 {
+		t.Fatal("no microdata pages")
+	}
+
+	for _, page := range pages {
+		t.Run(filepath.Base(page), func(t *testing.T) {
+			recipe := parseFixture(t, page)
+			parsed := goldenRecipe{
+				Name:        recipe.Name,
+				Yield:       recipe.Yield,
+				TotalTime:   recipe.TotalTime,
+				Image:       recipe.Image,
+				Ingredients: make([]goldenIngredient, 0),
+				Skipped:     recipe.Skipped,
+			}
+			for _, ingredient := range recipe.IngredientList().Ingredients {
+				parsed.Ingredients = append(parsed.Ingredients, goldenIngredient{
+					Line:   ingredient.Line,
+					Name:   ingredient.Name,
+					Amount: ingredient.Measure.Amount,
+					Unit:   ingredient.Measure.Name,
+				})
+			}
+
+			got, err := json.MarshalIndent(parsed, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			golden := strings.TrimSuffix(page, ".html") + ".golden"
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("%s does not match %s:\n%s", page, golden, got)
+			}
+		})
+	}
+}
@@ -1,11 +1,11 @@
 package parsing
 
 import (
-	"bytes"
+	"api/units"
 	"encoding/json"
 	"fmt"
-	"log"
 	"math"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -23,6 +23,23 @@ type Recipe struct {
 	FileContent string       `json:"file_content"`
 	Lines       []LineInfo   `json:"lines"`
 	Ingredients []Ingredient `json:"ingredients"`
+
+	// Name, TotalTime and Image are only known when the page describes its
+	// recipe in structured data. Yield and TotalTime are as written, e.g.
+	// "4 servings" and "PT1H30M".
+	Name      string `json:"name,omitempty"`
+	Yield     string `json:"yield,omitempty"`
+	TotalTime string `json:"total_time,omitempty"`
+	Image     string `json:"image,omitempty"`
+	// Servings is the number read from Yield, 0 when it is unknown
+	Servings float64 `json:"servings,omitempty"`
+	// Skipped are the candidate lines that turned out not to be ingredients
+	Skipped []string `json:"skipped,omitempty"`
+
+	// structured is set when the lines come from the page's structured data,
+	// which lists ingredients even when they have no amount, e.g. "salt to
+	// taste"
+	structured bool
 }
 
 // LineInfo has all the information for the parsing of a given line
@@ -74,42 +91,79 @@ func (r *Recipe) parseHTML() (rerr error) {
 		return
 	}
 
-	r.Lines, rerr = getIngredientLinesInHTML(r.FileContent)
+	doc, rerr := html.Parse(strings.NewReader(r.FileContent))
+	if rerr != nil {
+		return
+	}
+
+	// Structured data says exactly which lines are ingredients, the scorer
+	// only guesses
+	if data, ok := findJSONLDRecipe(doc); ok {
+		r.setRecipeData(data)
+	} else if data, ok := findMarkupRecipe(doc); ok {
+		r.setRecipeData(data)
+	} else {
+		r.Lines = getIngredientLinesInHTML(doc)
+	}
+	if r.Yield == "" {
+		r.Yield = findYieldInHTML(doc)
+	}
+	r.Servings = ParseServings(r.Yield)
 	return r.parseRecipe()
 
 }
 
+func (r *Recipe) setRecipeData(data recipeData) {
+	r.Name = data.Name
+	r.Yield = data.Yield
+	r.TotalTime = data.TotalTime
+	r.Image = data.Image
+	r.structured = true
+	r.Lines = make([]LineInfo, len(data.Ingredients))
+	for i, line := range data.Ingredients {
+		_, r.Lines[i] = scoreLine(line)
+	}
+}
+
 func (r *Recipe) parseRecipe() (rerr error) {
 	goodLines := make([]LineInfo, len(r.Lines))
 	j := 0
+	r.Skipped = nil
 	for _, lineInfo := range r.Lines {
 		if len(strings.TrimSpace(lineInfo.Line)) < 3 || len(strings.TrimSpace(lineInfo.Line)) > 150 {
+			r.skip(lineInfo)
 			continue
 		}
 		if strings.Contains(strings.ToLower(lineInfo.Line), "serving size") {
+			r.skip(lineInfo)
 			continue
 		}
 		if strings.Contains(strings.ToLower(lineInfo.Line), "yield") {
+			r.skip(lineInfo)
 			continue
 		}
 
 		// singularlize
 		lineInfo.Ingredient.Measure = Measure{}
 
-		// get amount, continue if there is an error
+		// get amount, continue if there is an error, unless the line is
+		// known to be an ingredient
 		err := lineInfo.getTotalAmount()
-		if err != nil {
+		if err != nil && !r.structured {
+			r.skip(lineInfo)
 			continue
 		}
 
 		// get ingredient, continue if its not found
 		err = lineInfo.getIngredient()
 		if err != nil {
+			r.skip(lineInfo)
 			continue
 		}
 
 		// get measure
 		lineInfo.getMeasure()
+		lineInfo.getConversions()
 
 		// get comment
 		if len(lineInfo.MeasureInString) > 0 && len(lineInfo.IngredientsInString) > 0 {
@@ -134,6 +188,7 @@ func (r *Recipe) parseRecipe() (rerr error) {
 						Name:   ingredients[line.Ingredient.Name].Measure.Name,
 						Amount: ingredients[line.Ingredient.Name].Measure.Amount + line.Ingredient.Measure.Amount,
 						Cups:   ingredients[line.Ingredient.Name].Measure.Cups + line.Ingredient.Measure.Cups,
+						Weight: ingredients[line.Ingredient.Name].Measure.Weight + line.Ingredient.Measure.Weight,
 					},
 				}
 			} else {
@@ -144,6 +199,7 @@ func (r *Recipe) parseRecipe() (rerr error) {
 						Name:   ingredients[line.Ingredient.Name].Measure.Name,
 						Amount: ingredients[line.Ingredient.Name].Measure.Amount,
 						Cups:   ingredients[line.Ingredient.Name].Measure.Cups + line.Ingredient.Measure.Cups,
+						Weight: ingredients[line.Ingredient.Name].Measure.Weight + line.Ingredient.Measure.Weight,
 					},
 				}
 			}
@@ -155,7 +211,8 @@ func (r *Recipe) parseRecipe() (rerr error) {
 				Measure: Measure{
 					Name:   line.Ingredient.Measure.Name,
 					Amount: line.Ingredient.Measure.Amount,
-					Cups:   line.Ingredient.Measure.Cups + line.Ingredient.Measure.Cups,
+					Cups:   line.Ingredient.Measure.Cups,
+					Weight: line.Ingredient.Measure.Weight,
 				},
 			}
 		}
@@ -168,6 +225,12 @@ func (r *Recipe) parseRecipe() (rerr error) {
 	return
 }
 
+func (r *Recipe) skip(lineInfo LineInfo) {
+	if line := strings.TrimSpace(lineInfo.LineOriginal); line != "" {
+		r.Skipped = append(r.Skipped, line)
+	}
+}
+
 func (lineInfo *LineInfo) getTotalAmount() (err error) {
 	lastPosition := -1
 	totalAmount := 0.0
@@ -210,11 +273,17 @@ func (lineInfo *LineInfo) getMeasure() (err error) {
 	return
 }
 
-func getIngredientLinesInHTML(htmlS string) (lineInfos []LineInfo, err error) {
-	doc, err := html.Parse(bytes.NewReader([]byte(htmlS)))
-	if err != nil {
-		return
-	}
+// getConversions fills in the cups and grams of the measure, where the
+// measure is a volume or weight and the ingredient's density is known if it
+// has to be converted from one to the other
+func (lineInfo *LineInfo) getConversions() {
+	measure := &lineInfo.Ingredient.Measure
+	unit := NormalizeMeasureName(measure.Name)
+	measure.Cups, _ = units.Convert(lineInfo.Ingredient.Name, measure.Amount, unit, units.Cup)
+	measure.Weight, _ = units.Convert(lineInfo.Ingredient.Name, measure.Amount, unit, units.Gram)
+}
+
+func getIngredientLinesInHTML(doc *html.Node) (lineInfos []LineInfo) {
 	var f func(n *html.Node, lineInfos *[]LineInfo) (s string, done bool)
 	f = func(n *html.Node, lineInfos *[]LineInfo) (s string, done bool) {
 		childrenLineInfo := []LineInfo{}
@@ -243,9 +312,6 @@ func getIngredientLinesInHTML(htmlS string) (lineInfos []LineInfo, err error) {
 		}
 		if score > 2 && len(childrenLineInfo) < 25 && len(childrenLineInfo) > 2 {
 			*lineInfos = append(*lineInfos, childrenLineInfo...)
-			for _, child := range childrenLineInfo {
-				log.Println("[%s]", child.LineOriginal)
-			}
 		}
 		if len(childrenLineInfo) > 0 {
 			// fmt.Println(childrenLineInfo)
@@ -413,9 +479,30 @@ func GetIngredientsInString(s string) (wordPositions []WordPosition) {
 	return getWordPositions(s, corpusIngredients)
 }
 
+// plainNumber matches the numbers the corpus does not list, e.g. the 200 of
+// "200 g carrots"
+var plainNumber = regexp.MustCompile(`\d+(?:\.\d+)?`)
+
 // GetNumbersInString returns the word positions of the numbers in the ingredient string
 func GetNumbersInString(s string) (wordPositions []WordPosition) {
-	return getWordPositions(s, corpusNumbers)
+	wordPositions = getWordPositions(s, corpusNumbers)
+
+	// Positions are those of the space before the number, as in the corpus
+	found := make(map[int]bool, len(wordPositions))
+	for _, wp := range wordPositions {
+		found[wp.Position] = true
+	}
+	for _, match := range plainNumber.FindAllStringIndex(s, -1) {
+		start, end := match[0], match[1]
+		if start == 0 || s[start-1] != ' ' || end == len(s) || s[end] != ' ' || found[start-1] {
+			continue
+		}
+		wordPositions = append(wordPositions, WordPosition{s[start:end], start - 1})
+	}
+	sort.Slice(wordPositions, func(i, j int) bool {
+		return wordPositions[i].Position < wordPositions[j].Position
+	})
+	return
 }
 
 // GetMeasuresInString returns the word positions of the measures in a ingredient string
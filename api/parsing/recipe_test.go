@@ -0,0 +1,29 @@
+package parsing
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetNumbersInString(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{" 200 g carrots ", []string{"200"}},
+		{" 1 1/2 cups milk ", []string{"1", "1/2"}},
+		{" 1.5 cups rice ", []string{"1.5"}},
+		{" 2 onions ", []string{"2"}},
+		{" v8 juice ", nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, number := range GetNumbersInString(tt.line) {
+			got = append(got, number.Word)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
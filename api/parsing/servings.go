@@ -0,0 +1,81 @@
+package parsing
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// yieldNumber is how many a yield says, a whole number, decimal, fraction or
+// mixed number such as "1 1/2" or "2½"
+const yieldNumber = `(?:\d+\s+\d+/\d+|\d+/\d+|\d+(?:\.\d+)?[½⅓⅔¼¾]?|[½⅓⅔¼¾])`
+
+// yieldInText finds how many a recipe feeds in the text of a page without
+// structured data, e.g. "Serves: 4", "Yield 12 cookies" or "6 servings"
+var yieldInText = regexp.MustCompile(`(?i)\b(?:serves|servings|yields?|portions)\s*:?\s*` + yieldNumber + `(?:\s*(?:-|to)\s*\d+)?|\b` + yieldNumber + `\s*(?:servings|portions|people)\b`)
+
+// servingsNumber reads the first number of a yield, the whole part and
+// fraction of a mixed number in their own groups
+var servingsNumber = regexp.MustCompile(`(?:(\d+)\s+)?(\d+)/(\d+)|(?:(\d+(?:\.\d+)?)\s*)?([½⅓⅔¼¾])|\d+(?:\.\d+)?`)
+
+var vulgarFractions = map[string]float64{"½": 1.0 / 2, "⅓": 1.0 / 3, "⅔": 2.0 / 3, "¼": 1.0 / 4, "¾": 3.0 / 4}
+
+// ParseServings reads the number of servings from a yield such as
+// "4 servings", "Serves 4-6" or "Makes 1 1/2 dozen", taking the lower end of
+// a range. It is 0 when the yield has no number.
+func ParseServings(yield string) float64 {
+	match := servingsNumber.FindStringSubmatch(yield)
+	switch {
+	case match == nil:
+		return 0
+	case match[3] != "":
+		whole, _ := strconv.ParseFloat(match[1], 64)
+		numerator, _ := strconv.ParseFloat(match[2], 64)
+		denominator, _ := strconv.ParseFloat(match[3], 64)
+		if denominator == 0 {
+			return whole
+		}
+		return whole + numerator/denominator
+	case match[5] != "":
+		whole, _ := strconv.ParseFloat(match[4], 64)
+		return whole + vulgarFractions[match[5]]
+	}
+
+	servings, _ := strconv.ParseFloat(match[0], 64)
+	return servings
+}
+
+// findYieldInHTML returns the first yield phrase in the page's text
+func findYieldInHTML(doc *html.Node) string {
+	text := strings.Join(strings.Fields(textContent(doc)), " ")
+	return yieldInText.FindString(text)
+}
+
+// Scale multiplies every amount so the recipe feeds the given number of
+// servings and returns the factor used. Recipes that don't say how many they
+// feed are left as they are, with a factor of 1.
+func (r *Recipe) Scale(servings float64) float64 {
+	if servings <= 0 || r.Servings <= 0 || servings == r.Servings {
+		return 1
+	}
+
+	scale := servings / r.Servings
+	for i := range r.Lines {
+		r.Lines[i].Ingredient.Measure.scale(scale)
+	}
+	for i := range r.Ingredients {
+		r.Ingredients[i].Measure.scale(scale)
+	}
+	r.Servings = servings
+
+	return scale
+}
+
+func (measure *Measure) scale(scale float64) {
+	measure.Amount = math.Round(measure.Amount*scale*100) / 100
+	measure.Cups *= scale
+	measure.Weight *= scale
+}
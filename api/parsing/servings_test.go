@@ -0,0 +1,109 @@
+package parsing
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestParseServings(t *testing.T) {
+	tests := []struct {
+		yield string
+		want  float64
+	}{
+		{"4 servings", 4},
+		{"Serves 4-6", 4},
+		{"Serves 4 - 6", 4},
+		{"4 to 6", 4},
+		{"Yield: 12 cookies", 12},
+		{"2.5 portions", 2.5},
+		{"1/2 loaf", 0.5},
+		{"Makes 1 1/2 dozen", 1.5},
+		{"Serves 2½", 2.5},
+		{"¾ cup", 0.75},
+		{"6", 6},
+		{"1/0 batch", 0},
+		{"Serves a crowd", 0},
+		{"one loaf", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := ParseServings(tt.yield); got != tt.want {
+			t.Errorf("ParseServings(%q) = %v, want %v", tt.yield, got, tt.want)
+		}
+	}
+}
+
+func TestFindYieldInHTML(t *testing.T) {
+	tests := []struct {
+		page string
+		want string
+	}{
+		{"<p>Serves: 4</p><p>Serves 8</p>", "Serves: 4"},
+		{"<div><span>Yield</span> <span>12</span> cookies</div>", "Yield 12"},
+		{"<p>Makes enough for 6 people</p>", "6 people"},
+		{"<p>Serves 4 to 6 as a side</p>", "Serves 4 to 6"},
+		{"<p>Yield: 1 1/2 dozen</p>", "Yield: 1 1/2"},
+		{"<p>2 cups flour</p>", ""},
+	}
+
+	for _, tt := range tests {
+		doc, err := html.Parse(strings.NewReader(tt.page))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := findYieldInHTML(doc); got != tt.want {
+			t.Errorf("findYieldInHTML(%q) = %q, want %q", tt.page, got, tt.want)
+		}
+	}
+}
+
+func TestScale(t *testing.T) {
+	const text = "Serves 3\n2 cups flour\n1 egg\n100 g butter"
+
+	tests := []struct {
+		servings float64
+		scale    float64
+		amounts  []float64
+	}{
+		{6, 2, []float64{4, 2, 200}},
+		// amounts are rounded to two decimals
+		{2, 2.0 / 3, []float64{1.33, 0.67, 66.67}},
+		{1, 1.0 / 3, []float64{0.67, 0.33, 33.33}},
+		// the same servings, or none asked for, leave the recipe as it is
+		{3, 1, []float64{2, 1, 100}},
+		{0, 1, []float64{2, 1, 100}},
+		{-2, 1, []float64{2, 1, 100}},
+	}
+
+	for _, tt := range tests {
+		recipe, err := NewFromText("bread", text)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if scale := recipe.Scale(tt.servings); scale != tt.scale {
+			t.Errorf("Scale(%v) = %v, want %v", tt.servings, scale, tt.scale)
+		}
+		ingredients := recipe.IngredientList().Ingredients
+		if len(ingredients) != len(tt.amounts) {
+			t.Fatalf("Scale(%v): got %d ingredients, want %d", tt.servings, len(ingredients), len(tt.amounts))
+		}
+		for i, ingredient := range ingredients {
+			if ingredient.Measure.Amount != tt.amounts[i] {
+				t.Errorf("Scale(%v): %s is %v, want %v", tt.servings, ingredient.Name, ingredient.Measure.Amount, tt.amounts[i])
+			}
+		}
+	}
+
+	// a recipe that doesn't say how many it feeds isn't scaled
+	recipe, err := NewFromText("bread", "2 cups flour")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scale := recipe.Scale(4); scale != 1 || recipe.IngredientList().Ingredients[0].Measure.Amount != 2 {
+		t.Errorf("without servings: got scale %v and %v cups, want 1 and 2", scale, recipe.IngredientList().Ingredients[0].Measure.Amount)
+	}
+}
@@ -0,0 +1,53 @@
+package parsing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownHeading  = regexp.MustCompile(`^#{1,6}\s+`)
+	markdownListItem = regexp.MustCompile(`^(?:[-*+•]|\d+[.)])\s+(?:\[[ xX]\]\s+)?`)
+	markdownLink     = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	markdownEmphasis = regexp.MustCompile("\\*\\*|__|`")
+)
+
+// NewFromText parses a recipe pasted as plain text or markdown, one
+// ingredient per line. Lines that aren't ingredients, like the method, are
+// left out the same way they are for web pages.
+func NewFromText(name, text string) (r *Recipe, err error) {
+	r = &Recipe{FileName: name, FileContent: text}
+	if strings.TrimSpace(text) == "" {
+		err = fmt.Errorf("no text to parse")
+		return
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = CleanTextLine(line)
+		if line == "" {
+			continue
+		}
+		if r.Yield == "" {
+			r.Yield = yieldInText.FindString(line)
+		}
+		_, lineInfo := scoreLine(line)
+		r.Lines = append(r.Lines, lineInfo)
+	}
+	r.Servings = ParseServings(r.Yield)
+
+	err = r.parseRecipe()
+	return
+}
+
+// CleanTextLine strips the markdown around a line, e.g. "- [ ] **2** cups
+// [flour](https://...)" is "2 cups flour". Ingredients parsed from text keep
+// the line as it is cleaned here.
+func CleanTextLine(line string) string {
+	line = strings.TrimSpace(line)
+	line = markdownHeading.ReplaceAllString(line, "")
+	line = markdownListItem.ReplaceAllString(line, "")
+	line = markdownLink.ReplaceAllString(line, "$1")
+	line = markdownEmphasis.ReplaceAllString(line, "")
+	return strings.Join(strings.Fields(line), " ")
+}
@@ -0,0 +1,89 @@
+package prices
+
+import (
+	"api/models"
+	"sort"
+)
+
+// Catalog answers price questions about grocery items from a snapshot of the
+// store_prices table
+type Catalog struct {
+	byName map[string][]models.StoreData
+	names  []string
+}
+
+func NewCatalog(storePrices []models.StoreData) *Catalog {
+	catalog := &Catalog{byName: make(map[string][]models.StoreData)}
+	for _, storePrice := range storePrices {
+		name := NormalizeName(storePrice.ItemName)
+		if _, ok := catalog.byName[name]; !ok {
+			catalog.names = append(catalog.names, name)
+		}
+		catalog.byName[name] = append(catalog.byName[name], storePrice)
+	}
+	sort.Strings(catalog.names)
+
+	return catalog
+}
+
+// Lookup returns the name and prices of the catalog item that best matches the
+// item name, cheapest first. Ties go to the shorter, more generic name.
+func (catalog *Catalog) Lookup(itemName string) (string, []models.StoreData, bool) {
+	name := NormalizeName(itemName)
+	if storePrices, ok := catalog.byName[name]; ok {
+		return name, sortedByPrice(storePrices), true
+	}
+
+	bestName, bestScore := "", 0.0
+	for _, candidate := range catalog.names {
+		score := Similarity(name, candidate)
+		if score > bestScore || (score == bestScore && len(candidate) < len(bestName)) {
+			bestName, bestScore = candidate, score
+		}
+	}
+
+	if bestScore < MatchThreshold {
+		return "", nil, false
+	}
+
+	return bestName, sortedByPrice(catalog.byName[bestName]), true
+}
+
+// Cheapest returns the store among stores with the lowest price for the item,
+// or among every store when stores is empty
+func (catalog *Catalog) Cheapest(itemName string, stores []models.StorePreference) (models.StorePreference, bool) {
+	_, storePrices, ok := catalog.Lookup(itemName)
+	if !ok {
+		return "", false
+	}
+
+	for _, storePrice := range storePrices {
+		if len(stores) == 0 || containsStore(stores, storePrice.StoreName) {
+			return storePrice.StoreName, true
+		}
+	}
+
+	return "", false
+}
+
+func sortedByPrice(storePrices []models.StoreData) []models.StoreData {
+	sorted := append([]models.StoreData(nil), storePrices...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Price != sorted[j].Price {
+			return sorted[i].Price < sorted[j].Price
+		}
+		return sorted[i].StoreName < sorted[j].StoreName
+	})
+
+	return sorted
+}
+
+func containsStore(stores []models.StorePreference, store models.StorePreference) bool {
+	for _, candidate := range stores {
+		if candidate == store {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,72 @@
+package prices
+
+import (
+	"api/models"
+	"testing"
+)
+
+func newTestCatalog() *Catalog {
+	return NewCatalog([]models.StoreData{
+		{ItemName: "Milk", StoreName: "Coles", Price: 1.60},
+		{ItemName: "milk", StoreName: "Aldi", Price: 1.49},
+		{ItemName: "sour cream", StoreName: "Aldi", Price: 2.00},
+		{ItemName: "cream cheese", StoreName: "Aldi", Price: 3.00},
+		{ItemName: "tomatoes", StoreName: "Woolworths", Price: 4.00},
+		{ItemName: "tomatoes", StoreName: "Coles", Price: 4.00},
+		{ItemName: "tomatoes", StoreName: "Aldi", Price: 4.50},
+	})
+}
+
+func TestCatalogLookup(t *testing.T) {
+	catalog := newTestCatalog()
+
+	tests := []struct {
+		itemName string
+		want     string
+	}{
+		{"🥛 Milk", "milk"},
+		{"tomatoe", "tomato"},
+		{"full cream milk", "milk"},
+		// both contain cream, and the shorter name wins the tie
+		{"cream", "sour cream"},
+		{"bread", ""},
+	}
+
+	for _, tt := range tests {
+		name, storePrices, ok := catalog.Lookup(tt.itemName)
+		if name != tt.want || ok != (tt.want != "") {
+			t.Errorf("Lookup(%q) = %q, %v, want %q", tt.itemName, name, ok, tt.want)
+		}
+		for i := 1; i < len(storePrices); i++ {
+			if storePrices[i].Price < storePrices[i-1].Price {
+				t.Errorf("Lookup(%q): prices are not cheapest first: %+v", tt.itemName, storePrices)
+			}
+		}
+	}
+}
+
+func TestCatalogCheapest(t *testing.T) {
+	catalog := newTestCatalog()
+
+	tests := []struct {
+		itemName string
+		stores   []models.StorePreference
+		want     models.StorePreference
+	}{
+		{"milk", nil, "Aldi"},
+		{"milk", []models.StorePreference{"Coles", "Woolworths"}, "Coles"},
+		// Coles and Woolworths charge the same, and ties go by name
+		{"tomatoes", nil, "Coles"},
+		{"tomatoes", []models.StorePreference{"Aldi", "Woolworths"}, "Woolworths"},
+		{"tomatoes", []models.StorePreference{"Aldi"}, "Aldi"},
+		{"sour cream", []models.StorePreference{"Coles"}, ""},
+		{"bread", nil, ""},
+	}
+
+	for _, tt := range tests {
+		store, ok := catalog.Cheapest(tt.itemName, tt.stores)
+		if store != tt.want || ok != (tt.want != "") {
+			t.Errorf("Cheapest(%q, %v) = %q, %v, want %q", tt.itemName, tt.stores, store, ok, tt.want)
+		}
+	}
+}
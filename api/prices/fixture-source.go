@@ -0,0 +1,163 @@
+package prices
+
+import (
+	"api/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FixtureSource reads prices for one store from a directory of files named
+// after the items, e.g. milk.json or full-cream-milk.html, so the refresh
+// pipeline can run without a network
+type FixtureSource struct {
+	store models.StorePreference
+	dir   string
+}
+
+var _ PriceSource = (*FixtureSource)(nil)
+var _ ItemLister = (*FixtureSource)(nil)
+
+func NewFixtureSource(store models.StorePreference, dir string) *FixtureSource {
+	return &FixtureSource{store: store, dir: dir}
+}
+
+// FixtureSources returns a FixtureSource for every subdirectory of dir, named
+// after the store it holds prices for, e.g. fixtures/aldi/milk.json
+func FixtureSources(dir string) ([]PriceSource, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price fixtures %s: %w", dir, err)
+	}
+
+	var sources []PriceSource
+	for _, entry := range entries {
+		if entry.IsDir() {
+			sources = append(sources, NewFixtureSource(models.StorePreference(entry.Name()), filepath.Join(dir, entry.Name())))
+		}
+	}
+
+	return sources, nil
+}
+
+func (source *FixtureSource) Store() models.StorePreference {
+	return source.store
+}
+
+// ListItems returns the item names of the fixture files
+func (source *FixtureSource) ListItems(ctx context.Context) ([]string, error) {
+	fixtures, err := source.fixtures()
+	if err != nil {
+		return nil, err
+	}
+
+	var itemNames []string
+	for itemName := range fixtures {
+		itemNames = append(itemNames, itemName)
+	}
+
+	return itemNames, nil
+}
+
+// fixtures maps normalized item names to their fixture files, so milk.json
+// and eggs.html are found for "Milk" and "egg"
+func (source *FixtureSource) fixtures() (map[string][]string, error) {
+	entries, err := os.ReadDir(source.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price fixtures %s: %w", source.dir, err)
+	}
+
+	fixtures := make(map[string][]string)
+	for _, entry := range entries {
+		extension := filepath.Ext(entry.Name())
+		if entry.IsDir() || (extension != ".json" && extension != ".html") {
+			continue
+		}
+		itemName := NormalizeName(strings.ReplaceAll(strings.TrimSuffix(entry.Name(), extension), "-", " "))
+		fixtures[itemName] = append(fixtures[itemName], filepath.Join(source.dir, entry.Name()))
+	}
+
+	// JSON fixtures are preferred over product pages
+	for _, paths := range fixtures {
+		sort.Slice(paths, func(i, j int) bool {
+			return filepath.Ext(paths[i]) == ".json" && filepath.Ext(paths[j]) != ".json"
+		})
+	}
+
+	return fixtures, nil
+}
+
+// FetchPrice reads the item's JSON fixture, a models.StoreData or just
+// {"price": 1.99}, or failing that its HTML fixture, a product page
+func (source *FixtureSource) FetchPrice(ctx context.Context, itemName string) (models.StoreData, error) {
+	storePrice := models.StoreData{
+		ItemName:    NormalizeName(itemName),
+		StoreName:   source.store,
+		LastUpdated: time.Now(),
+	}
+
+	fixtures, err := source.fixtures()
+	if err != nil {
+		return storePrice, err
+	}
+
+	paths, ok := fixtures[storePrice.ItemName]
+	if !ok {
+		return storePrice, fmt.Errorf("%s at %s: %w", itemName, source.store, ErrNoPrice)
+	}
+
+	path := paths[0]
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return storePrice, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if filepath.Ext(path) == ".json" {
+		var fixture models.StoreData
+		if err := json.Unmarshal(content, &fixture); err != nil {
+			return storePrice, fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		storePrice.Price = fixture.Price
+		if !fixture.LastUpdated.IsZero() {
+			storePrice.LastUpdated = fixture.LastUpdated
+		}
+		return storePrice, nil
+	}
+
+	price, ok := priceFromHTML(string(content))
+	if !ok {
+		return storePrice, fmt.Errorf("no price found in %s", path)
+	}
+	storePrice.Price = price
+
+	return storePrice, nil
+}
+
+// Product pages usually carry the price as schema.org markup; the first dollar
+// amount on the page is the fallback
+var htmlPricePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`itemprop=["']price["'][^>]*content=["']\$?([0-9]+(?:\.[0-9]+)?)["']`),
+	regexp.MustCompile(`content=["']\$?([0-9]+(?:\.[0-9]+)?)["'][^>]*itemprop=["']price["']`),
+	regexp.MustCompile(`"price"\s*:\s*"?\$?([0-9]+(?:\.[0-9]+)?)`),
+	regexp.MustCompile(`\$([0-9]+(?:\.[0-9]{2}))`),
+}
+
+func priceFromHTML(html string) (float64, bool) {
+	for _, pattern := range htmlPricePatterns {
+		if match := pattern.FindStringSubmatch(html); match != nil {
+			price, err := strconv.ParseFloat(match[1], 64)
+			if err == nil {
+				return price, true
+			}
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,92 @@
+package prices
+
+import (
+	"api/models"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvColumns are the header names ParseCSV understands. lastUpdated is
+// optional and defaults to the time of the import.
+var csvColumns = []string{"itemName", "storeName", "price", "lastUpdated"}
+
+// ParseCSV reads prices from a CSV with a header row naming its columns, e.g.
+//
+//	itemName,storeName,price,lastUpdated
+//	milk,aldi,1.99,2024-05-01T00:00:00Z
+func ParseCSV(r io.Reader, now time.Time) ([]models.StoreData, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, required := range csvColumns[:3] {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv header must have the columns %s", strings.Join(csvColumns, ", "))
+		}
+	}
+
+	var storePrices []models.StoreData
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv line %d: %w", line, err)
+		}
+
+		price, err := strconv.ParseFloat(strings.TrimPrefix(record[columns["price"]], "$"), 64)
+		if err != nil || math.IsNaN(price) || math.IsInf(price, 0) {
+			return nil, fmt.Errorf("line %d: invalid price %q", line, record[columns["price"]])
+		}
+
+		storePrice := models.StoreData{
+			ItemName:    record[columns["itemName"]],
+			StoreName:   models.StorePreference(record[columns["storeName"]]),
+			Price:       price,
+			LastUpdated: now,
+		}
+
+		if i, ok := columns["lastUpdated"]; ok && record[i] != "" {
+			storePrice.LastUpdated, err = time.Parse(time.RFC3339, record[i])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: lastUpdated must be an RFC 3339 time, got %q", line, record[i])
+			}
+		}
+
+		storePrices = append(storePrices, storePrice)
+	}
+
+	return storePrices, nil
+}
+
+// ParseJSON reads prices from a JSON array of models.StoreData. Entries
+// without lastUpdated get the time of the import.
+func ParseJSON(r io.Reader, now time.Time) ([]models.StoreData, error) {
+	var storePrices []models.StoreData
+	if err := json.NewDecoder(r).Decode(&storePrices); err != nil {
+		return nil, fmt.Errorf("failed to decode prices: %w", err)
+	}
+
+	for i := range storePrices {
+		if storePrices[i].LastUpdated.IsZero() {
+			storePrices[i].LastUpdated = now
+		}
+	}
+
+	return storePrices, nil
+}
@@ -0,0 +1,81 @@
+package prices
+
+import (
+	"api/models"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCSV(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	updated := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	// columns can come in any order, and lastUpdated can be left out
+	csv := "price, storeName, itemName, lastUpdated\n" +
+		"$1.99,Aldi,milk,2024-05-01T00:00:00Z\n" +
+		"3.5,Coles,sourdough bread,\n"
+	got, err := ParseCSV(strings.NewReader(csv), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.StoreData{
+		{ItemName: "milk", StoreName: "Aldi", Price: 1.99, LastUpdated: updated},
+		{ItemName: "sourdough bread", StoreName: "Coles", Price: 3.5, LastUpdated: now},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	got, err = ParseCSV(strings.NewReader("itemName,storeName,price\nmilk,Aldi,1.99\n"), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || !got[0].LastUpdated.Equal(now) {
+		t.Errorf("without a lastUpdated column: got %+v, want it updated now", got)
+	}
+
+	malformed := map[string]string{
+		"empty":           "",
+		"missing column":  "itemName,price\nmilk,1.99\n",
+		"short row":       "itemName,storeName,price\nmilk,Aldi\n",
+		"long row":        "itemName,storeName,price\nmilk,Aldi,1.99,extra\n",
+		"unquoted quote":  "itemName,storeName,price\n\"milk,Aldi,1.99\n",
+		"price not a num": "itemName,storeName,price\nmilk,Aldi,cheap\n",
+		"empty price":     "itemName,storeName,price\nmilk,Aldi,\n",
+		"NaN price":       "itemName,storeName,price\nmilk,Aldi,NaN\n",
+		"infinite price":  "itemName,storeName,price\nmilk,Aldi,Inf\n",
+		"bad lastUpdated": "itemName,storeName,price,lastUpdated\nmilk,Aldi,1.99,yesterday\n",
+	}
+	for name, csv := range malformed {
+		if got, err := ParseCSV(strings.NewReader(csv), now); err == nil {
+			t.Errorf("%s: got %+v, want an error", name, got)
+		}
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	got, err := ParseJSON(strings.NewReader(`[
+		{"itemName": "milk", "storeName": "Aldi", "price": 1.99, "lastUpdated": "2024-05-01T00:00:00Z"},
+		{"itemName": "bread", "storeName": "Coles", "price": 3.5}
+	]`), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.StoreData{
+		{ItemName: "milk", StoreName: "Aldi", Price: 1.99, LastUpdated: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{ItemName: "bread", StoreName: "Coles", Price: 3.5, LastUpdated: now},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	for _, body := range []string{``, `{"itemName": "milk"}`, `[{"price": "cheap"}]`} {
+		if got, err := ParseJSON(strings.NewReader(body), now); err == nil {
+			t.Errorf("ParseJSON(%q) = %+v, want an error", body, got)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package prices
+
+import (
+	"api/parsing"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+)
+
+// MatchThreshold is the lowest Similarity at which a catalog item is taken to
+// be the grocery item
+const MatchThreshold = 0.7
+
+// containedScore is the Similarity of names where every word of one is in the
+// other, e.g. "milk" and "full cream milk"
+const containedScore = 0.8
+
+// NormalizeName is the form item names are stored and compared in, so
+// "🍅 Tomatoes" and "tomato" are the same item
+func NormalizeName(itemName string) string {
+	return inflection.Singular(strings.Join(strings.Fields(parsing.ParseItemName(itemName)), " "))
+}
+
+// Similarity scores how alike two normalized names are, from 0 to 1. It is the
+// Dice coefficient of their letter pairs, which tolerates typos, raised to
+// containedScore when one name's words all appear in the other.
+func Similarity(a string, b string) float64 {
+	score := LetterSimilarity(a, b)
+	if containedScore > score && (containsWords(a, b) || containsWords(b, a)) {
+		score = containedScore
+	}
+
+	return score
+}
+
+// LetterSimilarity is Similarity without the lift for a name inside another,
+// for matches that act without being confirmed, where "milk" on a receipt
+// could be any of the milks on a list
+func LetterSimilarity(a string, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	return dice(bigrams(a), bigrams(b))
+}
+
+func bigrams(name string) map[string]int {
+	pairs := make(map[string]int)
+	for _, word := range strings.Fields(name) {
+		for i := 0; i+1 < len(word); i++ {
+			pairs[word[i:i+2]]++
+		}
+	}
+
+	return pairs
+}
+
+func dice(a map[string]int, b map[string]int) float64 {
+	total, shared := 0, 0
+	for pair, count := range a {
+		total += count
+		shared += min(count, b[pair])
+	}
+	for _, count := range b {
+		total += count
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	return float64(2*shared) / float64(total)
+}
+
+// containsWords reports whether every word of needle is a word of haystack
+func containsWords(haystack string, needle string) bool {
+	words := make(map[string]struct{})
+	for _, word := range strings.Fields(haystack) {
+		words[inflection.Singular(word)] = struct{}{}
+	}
+
+	needleWords := strings.Fields(needle)
+	if len(needleWords) == 0 {
+		return false
+	}
+	for _, word := range needleWords {
+		if _, ok := words[inflection.Singular(word)]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
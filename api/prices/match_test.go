@@ -0,0 +1,39 @@
+package prices
+
+import "testing"
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		match bool
+	}{
+		{"milk", "milk", true},
+		// a typo still matches
+		{"tomato", "tomatoe", true},
+		// every word of one name is a word of the other
+		{"milk", "full cream milk", true},
+		{"tomato", "cherry tomatoes", true},
+		{"full cream milk", "milk", true},
+		// close letters that are different items
+		{"milk", "silk", false},
+		{"chicken", "kitchen", false},
+		{"cream", "ice", false},
+		{"", "milk", false},
+	}
+
+	for _, tt := range tests {
+		if score := Similarity(tt.a, tt.b); (score >= MatchThreshold) != tt.match {
+			t.Errorf("Similarity(%q, %q) = %v, want a match %v", tt.a, tt.b, score, tt.match)
+		}
+	}
+
+	if score := Similarity("milk", "full cream milk"); score != containedScore {
+		t.Errorf("contained name: got %v, want %v", score, containedScore)
+	}
+	if score := LetterSimilarity("milk", "full cream milk"); score >= MatchThreshold {
+		t.Errorf("LetterSimilarity of a contained name: got %v, want below %v", score, MatchThreshold)
+	}
+	if score := LetterSimilarity("tomato", "tomatoe"); score < MatchThreshold {
+		t.Errorf("LetterSimilarity of a typo: got %v, want a match", score)
+	}
+}
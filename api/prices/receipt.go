@@ -0,0 +1,113 @@
+package prices
+
+import (
+	"api/extract"
+	"api/models"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// an amount at the end of a line, optionally followed by a tax flag like
+	// "A" or "*", e.g. "BANANAS CAVENDISH 3.05 #"
+	receiptPrice = regexp.MustCompile(`^(.*?)\s*(-?)\$?(\d{1,4})[.,](\d{2})(-?)(?:\s*[A-Z*#^]{1,2})?$`)
+	// the line a weighed or multi-bought item adds below its name,
+	// "0.870 kg NET @ $3.50/kg" or "2 x 1.49"
+	receiptQuantity = regexp.MustCompile(`(?i)^(?:qty\s*)?(?:\d+(?:[.,]\d+)?\s*(?:kg|g|ea|each)?\s*(?:net\s*)?[@x]|@)`)
+	// the price of one, or of a kilo, on a quantity line
+	receiptUnitPrice = regexp.MustCompile(`(?i)[@x]\s*\$?(\d{1,4})[.,](\d{2})`)
+	// lines that are about the sale rather than an item bought
+	receiptTotals = regexp.MustCompile(`(?i)\b(?:sub ?total|total|tax|gst|vat|change|cash|card|visa|mastercard|amex|eftpos|debit|credit|balance|saving|savings|saved|discount|rounding|due|tender|tendered|payment|paid|points|abn|invoice|receipt|items)\b`)
+	// a product code printed before the name
+	receiptCode = regexp.MustCompile(`^[*#]?\d{4,}\s+`)
+)
+
+// ParseReceipt finds the items bought on a receipt and what one of each, or a
+// kilo of a weighed one, cost. Totals, payments and discounts are left out, as
+// are lines that can't be read as a name with a price and items bought by
+// weight or in multiples whose unit price can't be read.
+func ParseReceipt(lines []extract.OCRLine) (items []models.ReceiptLine) {
+	// pending is a name printed without a price, waiting for the quantity
+	// line below it to give one
+	var pending *models.ReceiptLine
+	// priced is whether the last item was priced on the line just above, so
+	// a quantity line below it gives its unit price
+	priced := false
+
+	for _, line := range lines {
+		text := strings.Join(strings.Fields(line.Text), " ")
+		if text == "" || receiptTotals.MatchString(text) {
+			pending, priced = nil, false
+			continue
+		}
+		review := line.Confidence < extract.LowConfidence
+
+		match := receiptPrice.FindStringSubmatch(text)
+		if receiptQuantity.MatchString(text) {
+			unitPrice := receiptUnitPrice.FindStringSubmatch(text)
+			voided := match != nil && (match[2] != "" || match[5] != "")
+			switch {
+			case pending != nil && unitPrice != nil && !voided:
+				pending.Line += " " + text
+				pending.Price = receiptAmount(unitPrice[1], unitPrice[2])
+				pending.Review = pending.Review || review
+				items = append(items, *pending)
+			case priced && unitPrice != nil:
+				// "MILK 2L 2.98" above "2 @ $1.49"
+				last := &items[len(items)-1]
+				last.Line += " " + text
+				last.Price = receiptAmount(unitPrice[1], unitPrice[2])
+				last.Review = last.Review || review
+			case priced:
+				// what was paid is for more than one, so it isn't the price
+				items = items[:len(items)-1]
+			}
+			pending, priced = nil, false
+			continue
+		}
+
+		pending, priced = nil, false
+		if match == nil {
+			if name := receiptItemName(text); name != "" {
+				pending = &models.ReceiptLine{Line: text, Name: name, Review: review}
+			}
+			continue
+		}
+
+		// a negative amount is a discount or a voided item
+		if match[2] != "" || match[5] != "" {
+			continue
+		}
+
+		if name := receiptItemName(match[1]); name != "" {
+			items = append(items, models.ReceiptLine{Line: text, Name: name, Price: receiptAmount(match[3], match[4]), Review: review})
+			priced = true
+		}
+	}
+
+	return
+}
+
+func receiptAmount(dollars string, cents string) float64 {
+	amount, _ := strconv.ParseFloat(dollars+"."+cents, 64)
+	return amount
+}
+
+// receiptItemName strips the product code from a name and rejects what is
+// left if it isn't a word, like a stray barcode
+func receiptItemName(text string) string {
+	name := strings.TrimSpace(receiptCode.ReplaceAllString(text, ""))
+
+	letters := 0
+	for _, r := range name {
+		if ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') {
+			letters++
+		}
+	}
+	if letters < 2 {
+		return ""
+	}
+
+	return name
+}
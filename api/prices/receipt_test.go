@@ -0,0 +1,39 @@
+package prices
+
+import (
+	"api/extract"
+	"api/models"
+	"reflect"
+	"testing"
+)
+
+func TestParseReceipt(t *testing.T) {
+	lines := []extract.OCRLine{
+		{Text: "ALDI STORES", Confidence: 95},
+		{Text: "BANANAS", Confidence: 90},
+		{Text: "0.870 kg NET @ $3.50/kg 3.05", Confidence: 90},
+		{Text: "SKIM MILK 2.98", Confidence: 90},
+		{Text: "2 @ $1.49", Confidence: 60},
+		{Text: "9300617 SOURDOUGH BREAD 4.29 A", Confidence: 90},
+		{Text: "APPLES PINK LADY", Confidence: 90},
+		{Text: "0.640 kg NET @ $ /kg 2.88", Confidence: 90},
+		{Text: "EGGS 12PK 11.00", Confidence: 90},
+		{Text: "2 @", Confidence: 90},
+		{Text: "CHEESE 5.00", Confidence: 90},
+		{Text: "CHEESE 5.00-", Confidence: 90},
+		{Text: "SUBTOTAL 26.39", Confidence: 90},
+		{Text: "VISA 26.39", Confidence: 90},
+	}
+
+	// The apples' price per kilo and the price of one of the eggs can't be
+	// read, so what was paid for them isn't recorded as their price
+	want := []models.ReceiptLine{
+		{Line: "BANANAS 0.870 kg NET @ $3.50/kg 3.05", Name: "BANANAS", Price: 3.50},
+		{Line: "SKIM MILK 2.98 2 @ $1.49", Name: "SKIM MILK", Price: 1.49, Review: true},
+		{Line: "9300617 SOURDOUGH BREAD 4.29 A", Name: "SOURDOUGH BREAD", Price: 4.29},
+		{Line: "CHEESE 5.00", Name: "CHEESE", Price: 5},
+	}
+	if got := ParseReceipt(lines); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
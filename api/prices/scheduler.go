@@ -0,0 +1,309 @@
+package prices
+
+import (
+	"api/models"
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// priceStore is the part of proxy.Store the scheduler needs
+type priceStore interface {
+	ListStorePrices() ([]models.StoreData, error)
+	SaveStorePrices(storePrices []models.StoreData) error
+	ListPriceChecks() ([]models.PriceCheck, error)
+	SavePriceChecks(checks []models.PriceCheck) error
+}
+
+type SchedulerOptions struct {
+	// Interval is how often the catalog is checked for stale prices
+	Interval time.Duration
+	// MaxAge is how long a price, or a store not having one, is trusted before
+	// the store is asked again
+	MaxAge time.Duration
+	// RateLimit is the least time between two fetches from one source
+	RateLimit time.Duration
+	// Retries is how many times a failed fetch is tried again, waiting
+	// RetryDelay before the first retry and twice as long before each next one
+	Retries    int
+	RetryDelay time.Duration
+}
+
+// Scheduler keeps the price catalog fresh from the registered sources. Every
+// run asks each source for the items it was not asked about within MaxAge,
+// one goroutine per source so that each source is rate limited on its own.
+// Answers are recorded as price checks, so an item a store doesn't sell, or
+// whose price says it is older than MaxAge, is not fetched on every run.
+type Scheduler struct {
+	store   priceStore
+	sources []PriceSource
+	options SchedulerOptions
+
+	mu     sync.Mutex
+	status models.PriceRefreshStatus
+}
+
+func NewScheduler(store priceStore, sources []PriceSource, options SchedulerOptions) *Scheduler {
+	status := models.PriceRefreshStatus{Sources: make([]string, 0, len(sources))}
+	for _, source := range sources {
+		status.Sources = append(status.Sources, string(source.Store()))
+	}
+
+	return &Scheduler{store: store, sources: sources, options: options, status: status}
+}
+
+// Run refreshes straight away and then every Interval until ctx is done
+func (scheduler *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(scheduler.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := scheduler.Refresh(ctx); err != nil {
+			log.Printf("price refresh failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (scheduler *Scheduler) Status() models.PriceRefreshStatus {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+
+	status := scheduler.status
+	status.Sources = append([]string(nil), status.Sources...)
+	return status
+}
+
+// sourceResult is what one source's part of a run came to
+type sourceResult struct {
+	prices    []models.StoreData
+	checks    []models.PriceCheck
+	refreshed int
+	fresh     int
+	missing   int
+	failed    int
+	err       error
+}
+
+// Refresh runs one pass over every source
+func (scheduler *Scheduler) Refresh(ctx context.Context) error {
+	scheduler.mu.Lock()
+	if scheduler.status.Running {
+		scheduler.mu.Unlock()
+		return errors.New("a price refresh is already running")
+	}
+	scheduler.status.Running = true
+	scheduler.status.LastStarted = time.Now()
+	scheduler.mu.Unlock()
+
+	status, err := scheduler.refresh(ctx)
+
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+
+	status.Sources = scheduler.status.Sources
+	status.LastStarted = scheduler.status.LastStarted
+	status.LastFinished = time.Now()
+	if err != nil {
+		status.LastError = err.Error()
+	}
+	scheduler.status = status
+
+	return err
+}
+
+func (scheduler *Scheduler) refresh(ctx context.Context) (models.PriceRefreshStatus, error) {
+	var status models.PriceRefreshStatus
+
+	storePrices, err := scheduler.store.ListStorePrices()
+	if err != nil {
+		return status, err
+	}
+
+	checks, err := scheduler.store.ListPriceChecks()
+	if err != nil {
+		return status, err
+	}
+	lastChecked := lastCheckedPrices(storePrices, checks)
+
+	itemNames := make(map[string]struct{})
+	for _, storePrice := range storePrices {
+		itemNames[storePrice.ItemName] = struct{}{}
+	}
+
+	for _, source := range scheduler.sources {
+		lister, ok := source.(ItemLister)
+		if !ok {
+			continue
+		}
+		listed, err := lister.ListItems(ctx)
+		if err != nil {
+			return status, err
+		}
+		for _, itemName := range listed {
+			itemNames[NormalizeName(itemName)] = struct{}{}
+		}
+	}
+
+	results := make([]sourceResult, len(scheduler.sources))
+	var wg sync.WaitGroup
+	for i, source := range scheduler.sources {
+		wg.Add(1)
+		go func(i int, source PriceSource) {
+			defer wg.Done()
+			results[i] = scheduler.refreshSource(ctx, source, itemNames, lastChecked[source.Store()])
+		}(i, source)
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, result := range results {
+		status.Refreshed += result.refreshed
+		status.Fresh += result.fresh
+		status.Missing += result.missing
+		status.Failed += result.failed
+		if result.err != nil {
+			errs = append(errs, result.err)
+		}
+	}
+
+	// Counted after saving so it shows what is still stale, e.g. because a
+	// source is down
+	storePrices, err = scheduler.store.ListStorePrices()
+	if err == nil {
+		checks, err = scheduler.store.ListPriceChecks()
+	}
+	if err == nil {
+		lastChecked = lastCheckedPrices(storePrices, checks)
+		for _, storePrice := range storePrices {
+			if time.Since(lastChecked[storePrice.StoreName][storePrice.ItemName].at) > scheduler.options.MaxAge {
+				status.Stale++
+			}
+		}
+	}
+
+	return status, errors.Join(errs...)
+}
+
+// lastChecked is when a store last had, or did not have, a price for an item
+type lastChecked struct {
+	at    time.Time
+	found bool
+}
+
+// lastCheckedPrices keys by store and item name the later of each price's
+// LastUpdated and of the last check of the item at the store
+func lastCheckedPrices(storePrices []models.StoreData, checks []models.PriceCheck) map[models.StorePreference]map[string]lastChecked {
+	byStore := make(map[models.StorePreference]map[string]lastChecked)
+	record := func(store models.StorePreference, itemName string, checked lastChecked) {
+		if byStore[store] == nil {
+			byStore[store] = make(map[string]lastChecked)
+		}
+		if existing, ok := byStore[store][itemName]; !ok || checked.at.After(existing.at) {
+			byStore[store][itemName] = checked
+		}
+	}
+
+	for _, storePrice := range storePrices {
+		record(storePrice.StoreName, storePrice.ItemName, lastChecked{at: storePrice.LastUpdated, found: true})
+	}
+	for _, check := range checks {
+		record(check.StoreName, check.ItemName, lastChecked{at: check.CheckedAt, found: check.Found})
+	}
+
+	return byStore
+}
+
+func (scheduler *Scheduler) refreshSource(ctx context.Context, source PriceSource, itemNames map[string]struct{}, checked map[string]lastChecked) sourceResult {
+	var result sourceResult
+	var lastFetch time.Time
+
+	for itemName := range itemNames {
+		if last, ok := checked[itemName]; ok && time.Since(last.at) <= scheduler.options.MaxAge {
+			if last.found {
+				result.fresh++
+			} else {
+				result.missing++
+			}
+			continue
+		}
+
+		storePrice, err := scheduler.fetch(ctx, source, itemName, &lastFetch)
+		switch {
+		case errors.Is(err, ErrNoPrice):
+			result.checks = append(result.checks, models.PriceCheck{ItemName: itemName, StoreName: source.Store(), CheckedAt: time.Now(), Found: false})
+			result.missing++
+		case err != nil:
+			if ctx.Err() != nil {
+				result.err = ctx.Err()
+				return result
+			}
+			log.Printf("failed to fetch the price of %s at %s: %v", itemName, source.Store(), err)
+			result.failed++
+		default:
+			result.prices = append(result.prices, storePrice)
+			result.checks = append(result.checks, models.PriceCheck{ItemName: itemName, StoreName: source.Store(), CheckedAt: time.Now(), Found: true})
+			result.refreshed++
+		}
+	}
+
+	if len(result.prices) > 0 {
+		if result.err = scheduler.store.SaveStorePrices(result.prices); result.err != nil {
+			return result
+		}
+	}
+
+	// Failed fetches are left out, so they are tried again on the next run
+	if len(result.checks) > 0 {
+		result.err = scheduler.store.SavePriceChecks(result.checks)
+	}
+
+	return result
+}
+
+// fetch waits for the source's rate limit and retries failures with backoff
+func (scheduler *Scheduler) fetch(ctx context.Context, source PriceSource, itemName string, lastFetch *time.Time) (models.StoreData, error) {
+	delay := scheduler.options.RetryDelay
+
+	for attempt := 0; ; attempt++ {
+		if err := sleep(ctx, time.Until(lastFetch.Add(scheduler.options.RateLimit))); err != nil {
+			return models.StoreData{}, err
+		}
+		*lastFetch = time.Now()
+
+		storePrice, err := source.FetchPrice(ctx, itemName)
+		if err == nil || errors.Is(err, ErrNoPrice) || attempt >= scheduler.options.Retries {
+			storePrice.ItemName = itemName
+			storePrice.StoreName = source.Store()
+			return storePrice, err
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return models.StoreData{}, err
+		}
+		delay *= 2
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
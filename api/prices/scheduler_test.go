@@ -0,0 +1,143 @@
+package prices
+
+import (
+	"api/models"
+	"api/proxy/memory"
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingSource counts the fetches made from a fixture source
+type countingSource struct {
+	*FixtureSource
+	fetches atomic.Int32
+}
+
+func (source *countingSource) FetchPrice(ctx context.Context, itemName string) (models.StoreData, error) {
+	source.fetches.Add(1)
+	return source.FixtureSource.FetchPrice(ctx, itemName)
+}
+
+// failingSource is a store whose site is down
+type failingSource struct {
+	fetches atomic.Int32
+}
+
+func (source *failingSource) Store() models.StorePreference {
+	return "Woolworths"
+}
+
+func (source *failingSource) FetchPrice(ctx context.Context, itemName string) (models.StoreData, error) {
+	source.fetches.Add(1)
+	return models.StoreData{}, errors.New("503 Service Unavailable")
+}
+
+func newFixtureSources() (aldi *countingSource, coles *countingSource) {
+	fixtures := filepath.Join("testdata", "fixtures")
+	aldi = &countingSource{FixtureSource: NewFixtureSource("Aldi", filepath.Join(fixtures, "aldi"))}
+	coles = &countingSource{FixtureSource: NewFixtureSource("Coles", filepath.Join(fixtures, "coles"))}
+	return aldi, coles
+}
+
+func TestSchedulerRefresh(t *testing.T) {
+	store := memory.NewStore()
+	aldi, coles := newFixtureSources()
+	down := &failingSource{}
+	options := SchedulerOptions{MaxAge: time.Hour, Retries: 1, RetryDelay: time.Millisecond}
+	scheduler := NewScheduler(store, []PriceSource{aldi, coles, down}, options)
+
+	// Coles has no sourdough bread, and Woolworths is down
+	err := scheduler.Refresh(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := scheduler.Status()
+	if status.Refreshed != 3 || status.Missing != 1 || status.Failed != 2 || status.Fresh != 0 {
+		t.Errorf("first run: got %+v, want 3 refreshed, 1 missing and 2 failed", status)
+	}
+	if down.fetches.Load() != 4 {
+		t.Errorf("failing source: got %d fetches, want 2 items tried twice", down.fetches.Load())
+	}
+
+	storePrices, err := store.ListStorePrices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.StoreData{
+		{ItemName: "milk", StoreName: "Aldi", Price: 1.49},
+		{ItemName: "milk", StoreName: "Coles", Price: 1.60},
+		{ItemName: "sourdough bread", StoreName: "Aldi", Price: 4.29},
+	}
+	if len(storePrices) != len(want) {
+		t.Fatalf("got prices %+v, want %+v", storePrices, want)
+	}
+	for i, storePrice := range storePrices {
+		if storePrice.ItemName != want[i].ItemName || storePrice.StoreName != want[i].StoreName || storePrice.Price != want[i].Price {
+			t.Errorf("price %d: got %+v, want %+v", i, storePrice, want[i])
+		}
+	}
+
+	// Aldi's milk says it is from 2020, and Coles has no bread, but both were
+	// asked about within the hour so neither is fetched again. Woolworths is
+	// tried again since it never answered.
+	aldi.fetches.Store(0)
+	coles.fetches.Store(0)
+	down.fetches.Store(0)
+	if err := scheduler.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if fetches := aldi.fetches.Load() + coles.fetches.Load(); fetches != 0 {
+		t.Errorf("second run: got %d fixture fetches, want none", fetches)
+	}
+	if down.fetches.Load() != 4 {
+		t.Errorf("second run: got %d fetches from the failing source, want 4", down.fetches.Load())
+	}
+	status = scheduler.Status()
+	if status.Refreshed != 0 || status.Fresh != 3 || status.Missing != 1 || status.Stale != 0 {
+		t.Errorf("second run: got %+v, want 3 fresh, 1 missing and none stale", status)
+	}
+}
+
+func TestSchedulerRefreshAfterMaxAge(t *testing.T) {
+	store := memory.NewStore()
+	aldi, coles := newFixtureSources()
+
+	// An hour ago Coles had no milk and Aldi's was checked
+	checkedAt := time.Now().Add(-time.Hour)
+	err := store.SavePriceChecks([]models.PriceCheck{
+		{ItemName: "milk", StoreName: "Coles", CheckedAt: checkedAt, Found: false},
+		{ItemName: "milk", StoreName: "Aldi", CheckedAt: checkedAt, Found: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.SaveStorePrices([]models.StoreData{{ItemName: "milk", StoreName: "Aldi", Price: 1.29, LastUpdated: checkedAt}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scheduler := NewScheduler(store, []PriceSource{aldi, coles}, SchedulerOptions{MaxAge: 2 * time.Hour})
+	if err := scheduler.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if aldi.fetches.Load() != 1 || coles.fetches.Load() != 1 {
+		t.Errorf("within the max age: got %d Aldi and %d Coles fetches, want only the bread", aldi.fetches.Load(), coles.fetches.Load())
+	}
+
+	scheduler = NewScheduler(store, []PriceSource{aldi, coles}, SchedulerOptions{MaxAge: 30 * time.Minute})
+	aldi.fetches.Store(0)
+	coles.fetches.Store(0)
+	if err := scheduler.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if aldi.fetches.Load() != 1 || coles.fetches.Load() != 1 {
+		t.Errorf("past the max age: got %d Aldi and %d Coles fetches, want the milk again", aldi.fetches.Load(), coles.fetches.Load())
+	}
+	if status := scheduler.Status(); status.Refreshed != 2 || status.Fresh != 1 || status.Missing != 1 {
+		t.Errorf("past the max age: got %+v, want both milks refreshed", status)
+	}
+}
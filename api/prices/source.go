@@ -0,0 +1,26 @@
+package prices
+
+import (
+	"api/models"
+	"context"
+	"errors"
+)
+
+// ErrNoPrice is returned by a PriceSource whose store does not sell the item.
+// The scheduler does not retry it.
+var ErrNoPrice = errors.New("no price")
+
+// PriceSource fetches current prices from one store. Supermarket adapters
+// implement it; FixtureSource is the offline one.
+type PriceSource interface {
+	// Store is the store every price from the source is for
+	Store() models.StorePreference
+	// FetchPrice returns the current price of the item at the store
+	FetchPrice(ctx context.Context, itemName string) (models.StoreData, error)
+}
+
+// ItemLister is implemented by sources that know which items they sell, so
+// the scheduler can add items that are not in the catalog yet
+type ItemLister interface {
+	ListItems(ctx context.Context) ([]string, error)
+}
@@ -0,0 +1,102 @@
+package providers
+
+import (
+	"api/data"
+	"api/models"
+	"api/parsing"
+	"api/proxy"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+)
+
+// categoryOverrides returns the household's overrides keyed by item name
+func categoryOverrides(store proxy.Store, householdId string) (map[string]string, error) {
+	overrides, err := store.ListCategoryOverrides(householdId)
+	if err != nil {
+		return nil, err
+	}
+
+	byItemName := make(map[string]string, len(overrides))
+	for _, override := range overrides {
+		byItemName[override.ItemName] = override.Category
+	}
+
+	return byItemName, nil
+}
+
+// lookupCategory tries the whole name and then shorter endings of it, so
+// "red onions" falls back to "onion". Each is tried as typed, singular and
+// plural since data.Categories has a mix of both. A word that is an item
+// itself isn't dropped, since "almond butter" is no more butter than
+// "peanut butter" is.
+func lookupCategory(overrides map[string]string, itemName string) string {
+	words := strings.Fields(parsing.ParseItemName(itemName))
+
+	for start := range words {
+		if category := knownCategory(overrides, strings.Join(words[start:], " ")); category != "" {
+			return category
+		}
+		if knownCategory(overrides, words[start]) != "" {
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// knownCategory looks the name up in the household's overrides and then in
+// data.Categories, as typed, singular and plural
+func knownCategory(overrides map[string]string, name string) string {
+	candidates := []string{name, inflection.Singular(name), inflection.Plural(name)}
+
+	for _, candidate := range candidates {
+		if category, ok := overrides[candidate]; ok {
+			return category
+		}
+	}
+	for _, candidate := range candidates {
+		if category, ok := data.Categories[candidate]; ok {
+			return category
+		}
+	}
+
+	return ""
+}
+
+// categorizeGroceryItem fills in the category of groceries the client did not
+// categorize. Tasks are left alone.
+func categorizeGroceryItem(overrides map[string]string, groceryItem models.GroceryItem) models.GroceryItem {
+	if groceryItem.Kind == models.TaskKind || groceryItem.Category != "" {
+		return groceryItem
+	}
+
+	groceryItem.Category = lookupCategory(overrides, groceryItem.Name)
+
+	return groceryItem
+}
+
+func GetCategoryOverrides(store proxy.Store, householdId string) ([]models.CategoryOverride, error) {
+	if _, err := store.GetHousehold(householdId); err != nil {
+		return nil, err
+	}
+
+	return store.ListCategoryOverrides(householdId)
+}
+
+// SaveCategoryOverride stores the override under the parsed item name so it
+// matches however the item is typed in later
+func SaveCategoryOverride(store proxy.Store, override models.CategoryOverride) (*models.CategoryOverride, error) {
+	override.ItemName = parsing.ParseItemName(override.ItemName)
+	override.Category = strings.TrimSpace(override.Category)
+
+	if err := store.SaveCategoryOverride(override); err != nil {
+		return nil, err
+	}
+
+	return &override, nil
+}
+
+func DeleteCategoryOverride(store proxy.Store, householdId string, itemName string) error {
+	return store.DeleteCategoryOverride(householdId, parsing.ParseItemName(itemName))
+}
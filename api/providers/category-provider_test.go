@@ -0,0 +1,89 @@
+package providers
+
+import (
+	"api/models"
+	"api/proxy/memory"
+	"testing"
+)
+
+func TestLookupCategory(t *testing.T) {
+	overrides := map[string]string{"tofu": "Meat"}
+
+	tests := []struct {
+		itemName string
+		want     string
+	}{
+		{"Milk", "Dairy"},
+		{"🧅 Onions", "Vegetable"},
+		// the whole name is tried first, then shorter endings of it
+		{"red onions", "Vegetable"},
+		{"plain flour", "Grain"},
+		{"Tofu", "Meat"},
+		{"smoked tofu", "Meat"},
+		{"something new", ""},
+		// a multiword name in the table is not read as its last word
+		{"peanut butter", "Pantry"},
+		{"Crunchy peanut butter", "Pantry"},
+		{"coconut milk", "Dairy"},
+		{"light coconut milk", "Dairy"},
+		{"coconut oil", "Pantry"},
+		// and one that isn't is not taken for its last word when the words
+		// before it name an item too
+		{"almond butter", ""},
+		{"apple butter", ""},
+		// a household override names an item as well
+		{"tofu butter", ""},
+	}
+
+	for _, tt := range tests {
+		if got := lookupCategory(overrides, tt.itemName); got != tt.want {
+			t.Errorf("lookupCategory(%q) = %q, want %q", tt.itemName, got, tt.want)
+		}
+	}
+}
+
+func TestCategoryOverrides(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+
+	override, err := SaveCategoryOverride(store, models.CategoryOverride{HouseholdId: household.Id, ItemName: " 🧅 Onion", Category: " Pantry "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if override.ItemName != "onion" || override.Category != "Pantry" {
+		t.Errorf("SaveCategoryOverride: got %+v, want it stored as onion in Pantry", *override)
+	}
+
+	for _, item := range []models.GroceryItem{
+		{Id: "onions", HouseholdId: household.Id, Name: "Pickled onions", Kind: models.GroceryKind},
+		{Id: "milk", HouseholdId: household.Id, Name: "Milk", Kind: models.GroceryKind},
+		{Id: "cheese", HouseholdId: household.Id, Name: "Cheese", Kind: models.GroceryKind, Category: "Deli"},
+		{Id: "task", HouseholdId: household.Id, Name: "Peel onions", Kind: models.TaskKind},
+	} {
+		if err := CreateGroceryItem(store, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := map[string]string{"onions": "Pantry", "milk": "Dairy", "cheese": "Deli", "task": ""}
+	for id, category := range want {
+		item, err := store.GetGroceryItem(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.Category != category {
+			t.Errorf("%s: got category %q, want %q", item.Name, item.Category, category)
+		}
+	}
+
+	if err := DeleteCategoryOverride(store, household.Id, "Onion"); err != nil {
+		t.Fatal(err)
+	}
+	overrides, err := GetCategoryOverrides(store, household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overrides) != 0 {
+		t.Errorf("overrides after deleting: got %+v", overrides)
+	}
+}
@@ -2,74 +2,88 @@ package providers
 
 import (
 	"api/models"
-	db "api/proxy/sqlite"
+	"api/proxy"
 	"fmt"
 )
 
 var groceriesTableName = "Groceries"
 
-func GetGroceryItems(householdId string) ([]models.GroceryItem, error) {
-	database, _ := db.NewDB()
-	defer database.Close()
-
-	_, err := GetOrCreateHousehold(householdId)
+func GetGroceryItems(store proxy.Store, householdId string) ([]models.GroceryItem, error) {
+	_, err := GetOrCreateHousehold(store, householdId)
 	if err != nil {
-		return nil, fmt.Errorf("Could not get or create household %w: %w", householdId, err)
+		return nil, fmt.Errorf("Could not get or create household %s: %w", householdId, err)
 	}
 
-	return database.ListGroceryItemsByHousehold(householdId)
+	return store.ListGroceryItemsByHousehold(householdId)
 }
 
-func GetSchedule(taskIds []string) ([]models.TaskScheduleItem, error) {
-	database, _ := db.NewDB()
-	defer database.Close()
-
-	return database.GetTaskSchedule(taskIds)
+func GetSchedule(store proxy.Store, taskIds []string) ([]models.TaskScheduleItem, error) {
+	return store.GetTaskSchedule(taskIds)
 }
 
-func CreateTaskSchedule(request models.ScheduleTaskRequest) error {
-	database, _ := db.NewDB()
-	defer database.Close()
-
-	return database.CreateTaskSchedule(request.TaskId, request.Dates)
+func CreateTaskSchedule(store proxy.Store, request models.ScheduleTaskRequest) error {
+	return store.CreateTaskSchedule(request.TaskId, request.Dates)
 }
 
-func CreateGroceryItem(groceryItem models.GroceryItem) error {
-	database, _ := db.NewDB()
-	defer database.Close()
-
-	household, _ := database.GetHousehold(groceryItem.HouseholdId)
+func CreateGroceryItem(store proxy.Store, groceryItem models.GroceryItem) error {
+	household, _ := store.GetHousehold(groceryItem.HouseholdId)
 	if household == nil {
-		database.CreateUserHousehold(groceryItem.HouseholdId)
+		store.CreateUserHousehold(groceryItem.HouseholdId)
+	}
+
+	if _, err := FindGroceryList(store, groceryItem.HouseholdId, groceryItem.ListId); err != nil {
+		return err
+	}
+
+	overrides, err := categoryOverrides(store, groceryItem.HouseholdId)
+	if err != nil {
+		return err
 	}
 
-	_, err := database.CreateGroceryItem(groceryItem.Name, groceryItem.Kind, groceryItem.Category, groceryItem.HouseholdId)
+	_, err = store.CreateGroceryItem(categorizeGroceryItem(overrides, groceryItem))
 
 	return err
 }
 
-func UpdateGroceryItem(groceryItem models.GroceryItem) error {
-	database, _ := db.NewDB()
-	defer database.Close()
-
-	return database.UpdateGroceryItemStatus(groceryItem.Id, groceryItem.Checked)
+func UpdateGroceryItem(store proxy.Store, groceryItem models.GroceryItem) error {
+	return store.UpdateGroceryItemStatus(groceryItem.Id, groceryItem.Checked)
 }
 
-func DeleteGroceryItem(householdId string, groceryItemId string) error {
-	database, _ := db.NewDB()
-	defer database.Close()
+func PatchGroceryItem(store proxy.Store, groceryItemId string, patch models.GroceryItemPatch) (*models.GroceryItem, error) {
+	// Items can only be moved between lists of their own household
+	if patch.ListId != nil {
+		groceryItem, err := store.GetGroceryItem(groceryItemId)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := FindGroceryList(store, groceryItem.HouseholdId, *patch.ListId); err != nil {
+			return nil, err
+		}
+	}
+
+	groceryItem, err := store.UpdateGroceryItem(groceryItemId, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.StoreOverride != nil {
+		if err := RecordStoreChoice(store, groceryItem.HouseholdId, groceryItem.Name, *patch.StoreOverride); err != nil {
+			return nil, err
+		}
+	}
 
-	return database.DeleteGroceryItems([]string{groceryItemId})
+	return groceryItem, nil
 }
 
-func BatchDeleteGroceryItems(groceryItems []models.GroceryItem) error {
-	database, _ := db.NewDB()
-	defer database.Close()
+func DeleteGroceryItem(store proxy.Store, householdId string, groceryItemId string) error {
+	return store.DeleteGroceryItems([]string{groceryItemId})
+}
 
+func BatchDeleteGroceryItems(store proxy.Store, groceryItems []models.GroceryItem) error {
 	ids := make([]string, len(groceryItems))
 	for i, item := range groceryItems {
 		ids[i] = item.Id
 	}
 
-	return database.DeleteGroceryItems(ids)
+	return store.DeleteGroceryItems(ids)
 }
@@ -0,0 +1,127 @@
+package providers
+
+import (
+	"api/models"
+	"api/proxy"
+	"errors"
+	"fmt"
+)
+
+// ErrDeleteDefaultGroceryList is returned when deleting a household's default
+// list, which items without a list id are put on
+var ErrDeleteDefaultGroceryList = errors.New("the default grocery list cannot be deleted")
+
+// FindGroceryList returns the household's list with the given id, or its
+// default list when listId is empty. Lists of other households are not found.
+func FindGroceryList(store proxy.Store, householdId string, listId string) (*models.GroceryList, error) {
+	_, err := GetOrCreateHousehold(store, householdId)
+	if err != nil {
+		return nil, fmt.Errorf("Could not get or create household %s: %w", householdId, err)
+	}
+
+	if listId == "" {
+		listId = householdId
+	}
+
+	list, err := store.GetGroceryList(listId)
+	if err != nil {
+		return nil, err
+	}
+	if list.HouseholdId != householdId {
+		return nil, fmt.Errorf("grocery list %w", proxy.ErrNotFound)
+	}
+
+	return list, nil
+}
+
+// GetGroceryList returns the list with its items and reconciled layout
+func GetGroceryList(store proxy.Store, householdId string, listId string) (*models.GroceryList, error) {
+	list, err := FindGroceryList(store, householdId, listId)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadGroceryList(store, *list)
+}
+
+// GetGroceryLists returns every list of the household with its items and layout
+func GetGroceryLists(store proxy.Store, householdId string) ([]models.GroceryList, error) {
+	_, err := GetOrCreateHousehold(store, householdId)
+	if err != nil {
+		return nil, fmt.Errorf("Could not get or create household %s: %w", householdId, err)
+	}
+
+	lists, err := store.ListGroceryLists(householdId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, list := range lists {
+		loaded, err := loadGroceryList(store, list)
+		if err != nil {
+			return nil, err
+		}
+		lists[i] = *loaded
+	}
+
+	return lists, nil
+}
+
+func loadGroceryList(store proxy.Store, list models.GroceryList) (*models.GroceryList, error) {
+	groceryItems, err := store.ListGroceryItemsByList(list.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	layout, err := GetLayout(store, list.Id, groceryItems)
+	if err != nil {
+		return nil, err
+	}
+
+	list.Items = groceryItems
+	list.Layout = layout
+
+	return &list, nil
+}
+
+func CreateGroceryList(store proxy.Store, householdId string, name string) (*models.GroceryList, error) {
+	_, err := GetOrCreateHousehold(store, householdId)
+	if err != nil {
+		return nil, fmt.Errorf("Could not get or create household %s: %w", householdId, err)
+	}
+
+	list, err := store.CreateGroceryList(models.GroceryList{HouseholdId: householdId, Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	return loadGroceryList(store, *list)
+}
+
+func RenameGroceryList(store proxy.Store, householdId string, listId string, name string) (*models.GroceryList, error) {
+	list, err := FindGroceryList(store, householdId, listId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.RenameGroceryList(list.Id, name); err != nil {
+		return nil, err
+	}
+	list.Name = name
+
+	return loadGroceryList(store, *list)
+}
+
+// DeleteGroceryList deletes the list and everything on it
+func DeleteGroceryList(store proxy.Store, householdId string, listId string) error {
+	list, err := FindGroceryList(store, householdId, listId)
+	if err != nil {
+		return err
+	}
+
+	if list.Id == householdId {
+		return ErrDeleteDefaultGroceryList
+	}
+
+	return store.DeleteGroceryList(list.Id)
+}
@@ -0,0 +1,124 @@
+package providers
+
+import (
+	"api/merge"
+	"api/models"
+	"api/proxy"
+	"api/proxy/memory"
+	"errors"
+	"testing"
+)
+
+func TestFindGroceryList(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+	neighbours := newTestHousehold(t, store)
+	party, err := CreateGroceryList(store, household.Id, "Party")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := FindGroceryList(store, household.Id, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Id != household.Id || list.Name != models.DefaultGroceryListName {
+		t.Errorf("no list id: got %+v, want the default list", *list)
+	}
+
+	list, err = FindGroceryList(store, household.Id, party.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Name != "Party" {
+		t.Errorf("got %+v, want the Party list", *list)
+	}
+
+	if _, err := FindGroceryList(store, neighbours.Id, party.Id); !errors.Is(err, proxy.ErrNotFound) {
+		t.Errorf("another household's list: got %v, want ErrNotFound", err)
+	}
+
+	// households are created the first time they are used
+	list, err = FindGroceryList(store, "new-household", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Id != "new-household" {
+		t.Errorf("new household: got %+v, want its default list", *list)
+	}
+}
+
+func TestGroceryListItems(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+	neighbours := newTestHousehold(t, store)
+	party, err := CreateGroceryList(store, household.Id, "Party")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CreateGroceryItem(store, models.GroceryItem{Id: "bread", HouseholdId: household.Id, Name: "Bread", Kind: models.GroceryKind}); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateGroceryItem(store, models.GroceryItem{HouseholdId: household.Id, ListId: party.Id, Name: "Chips", Kind: models.GroceryKind}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := MergeIngredients(store, household.Id, party.Id, []merge.Ingredient{{Name: "salsa", Quantity: 1, Unit: "jar"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	lists, err := GetGroceryLists(store, household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lists) != 2 {
+		t.Fatalf("got %d lists, want the default list and Party", len(lists))
+	}
+	if names := itemNames(lists[0].Items); len(names) != 1 || names[0] != "Bread" {
+		t.Errorf("default list: got %q", names)
+	}
+	if names := itemNames(lists[1].Items); len(names) != 2 || names[0] != "Chips" || names[1] != "salsa" {
+		t.Errorf("Party list: got %q", names)
+	}
+	if len(lists[1].Layout) != 2 {
+		t.Errorf("Party layout: got %+v, want a block per item", lists[1].Layout)
+	}
+
+	neighboursList := neighbours.Id
+	if _, err := PatchGroceryItem(store, "bread", models.GroceryItemPatch{ListId: &neighboursList}); !errors.Is(err, proxy.ErrNotFound) {
+		t.Errorf("moving to another household's list: got %v, want ErrNotFound", err)
+	}
+	moved, err := PatchGroceryItem(store, "bread", models.GroceryItemPatch{ListId: &party.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.ListId != party.Id {
+		t.Errorf("moved item: got list %q, want %q", moved.ListId, party.Id)
+	}
+
+	if err := DeleteGroceryList(store, household.Id, household.Id); !errors.Is(err, ErrDeleteDefaultGroceryList) {
+		t.Errorf("deleting the default list: got %v, want ErrDeleteDefaultGroceryList", err)
+	}
+	if err := DeleteGroceryList(store, neighbours.Id, party.Id); !errors.Is(err, proxy.ErrNotFound) {
+		t.Errorf("deleting another household's list: got %v, want ErrNotFound", err)
+	}
+	if err := DeleteGroceryList(store, household.Id, party.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := GetGroceryItems(store, household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 0 {
+		t.Errorf("items left after deleting their list: %q", itemNames(items))
+	}
+}
+
+func itemNames(items []models.GroceryItem) []string {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+	}
+	return names
+}
@@ -2,40 +2,50 @@ package providers
 
 import (
 	"api/models"
-	db "api/proxy/sqlite"
+	"api/proxy"
 )
 
-func CreateHousehold() *models.Household {
-	database, _ := db.NewDB()
-	defer database.Close()
+func CreateHousehold(store proxy.Store) (*models.Household, error) {
+	return store.CreateHousehold("")
+}
 
-	household, _ := database.CreateHousehold("")
-	return household
+func GetHousehold(store proxy.Store, householdId string) (*models.Household, error) {
+	return store.GetHousehold(householdId)
 }
 
-func JoinHousehold(userId string, householdId string) error {
-	database, _ := db.NewDB()
-	defer database.Close()
+// SetMeasurementSystem changes how quantities of recipes imported from now on
+// are written. Items already on the list keep their units.
+func SetMeasurementSystem(store proxy.Store, householdId string, system models.MeasurementSystem) (*models.Household, error) {
+	if err := store.SetHouseholdMeasurementSystem(householdId, system); err != nil {
+		return nil, err
+	}
 
-	return database.AddUserToHousehold(userId, householdId)
+	return store.GetHousehold(householdId)
 }
 
-func LeaveHousehold(userId string, householdIdToRemove string) error {
-	database, _ := db.NewDB()
-	defer database.Close()
-
-	return database.RemoveUserFromHousehold(userId, householdIdToRemove)
+func JoinHousehold(store proxy.Store, userId string, householdId string) error {
+	return store.AddUserToHousehold(userId, householdId)
 }
 
-func GetOrCreateHousehold(id string) (*models.Household, error) {
-	database, _ := db.NewDB()
-	defer database.Close()
+func LeaveHousehold(store proxy.Store, userId string, householdIdToRemove string) error {
+	return store.RemoveUserFromHousehold(userId, householdIdToRemove)
+}
 
-	household, err := database.GetHousehold(id)
+func GetOrCreateHousehold(store proxy.Store, id string) (*models.Household, error) {
+	household, err := store.GetHousehold(id)
 
 	if err == nil && household != nil {
 		return household, nil
 	}
 
-	return database.CreateUserHousehold(id)
+	household, err = store.CreateUserHousehold(id)
+	if err != nil {
+		// Another request may have created it since the lookup above
+		if existing, getErr := store.GetHousehold(id); getErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+
+	return household, nil
 }
@@ -0,0 +1,157 @@
+package providers
+
+import (
+	"api/models"
+	"api/proxy"
+	"fmt"
+)
+
+// GetLayout returns the list's saved layout, reconciled against its items
+func GetLayout(store proxy.Store, listId string, groceryItems []models.GroceryItem) ([]models.LayoutBlock, error) {
+	layout, err := store.GetLayout(listId)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReconcileLayout(layout, groceryItems), nil
+}
+
+func SaveLayout(store proxy.Store, householdId string, listId string, layout []models.LayoutBlock) ([]models.LayoutBlock, error) {
+	list, err := FindGroceryList(store, householdId, listId)
+	if err != nil {
+		return nil, err
+	}
+
+	groceryItems, err := store.ListGroceryItemsByList(list.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	layout = ReconcileLayout(layout, groceryItems)
+	if err := store.SaveLayout(list.Id, layout); err != nil {
+		return nil, err
+	}
+
+	return layout, nil
+}
+
+// MoveGroceryItem takes the item out of wherever it is in the layout and puts
+// it in the requested section, creating the section if it does not exist yet
+func MoveGroceryItem(store proxy.Store, householdId string, listId string, request models.MoveGroceryItemRequest) ([]models.LayoutBlock, error) {
+	list, err := FindGroceryList(store, householdId, listId)
+	if err != nil {
+		return nil, err
+	}
+
+	groceryItems, err := store.ListGroceryItemsByList(list.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	layout, err := GetLayout(store, list.Id, groceryItems)
+	if err != nil {
+		return nil, err
+	}
+
+	from := -1
+	for i, block := range layout {
+		if block.Type == models.GroceryItemId && block.Value == request.ItemId {
+			from = i
+			break
+		}
+	}
+	if from == -1 {
+		return nil, fmt.Errorf("grocery item %w", proxy.ErrNotFound)
+	}
+	moved := layout[from]
+	layout = append(layout[:from:from], layout[from+1:]...)
+
+	start := 0
+	if request.Section != "" {
+		start = -1
+		for i, block := range layout {
+			if block.Type == models.Text && block.Value == request.Section {
+				start = i + 1
+				break
+			}
+		}
+		if start == -1 {
+			layout = append(layout, models.LayoutBlock{Type: models.Text, Value: request.Section})
+			start = len(layout)
+		}
+	}
+
+	end := start
+	for end < len(layout) && layout[end].Type != models.Text {
+		end++
+	}
+
+	to := end
+	if request.Index >= 0 && start+request.Index < end {
+		to = start + request.Index
+	}
+
+	layout = append(layout[:to:to], append([]models.LayoutBlock{moved}, layout[to:]...)...)
+
+	layout = ReconcileLayout(layout, groceryItems)
+	if err := store.SaveLayout(list.Id, layout); err != nil {
+		return nil, err
+	}
+
+	// Sections are store headers after GroceryMagic, so moving an item into
+	// one is remembered as buying it there. Other headers, like "Dinner", are
+	// not stores and are left out of the history.
+	stores, err := knownStores(store, householdId, request.PreferredStores)
+	if err != nil {
+		return nil, err
+	}
+	if stores[models.StorePreference(request.Section)] {
+		for _, groceryItem := range groceryItems {
+			if groceryItem.Id != request.ItemId {
+				continue
+			}
+			err := RecordStoreChoice(store, householdId, groceryItem.Name, models.StorePreference(request.Section))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return layout, nil
+}
+
+// ReconcileLayout drops blocks for items that no longer exist, puts items that
+// are not in the layout yet at the top, and drops sections left empty
+func ReconcileLayout(layout []models.LayoutBlock, groceryItems []models.GroceryItem) []models.LayoutBlock {
+	remaining := make(map[string]struct{}, len(groceryItems))
+	for _, item := range groceryItems {
+		remaining[item.Id] = struct{}{}
+	}
+
+	var placed []models.LayoutBlock
+	for _, block := range layout {
+		if block.Type == models.GroceryItemId {
+			if _, ok := remaining[block.Value]; !ok {
+				continue
+			}
+			delete(remaining, block.Value)
+		}
+		placed = append(placed, block)
+	}
+
+	reconciled := make([]models.LayoutBlock, 0, len(groceryItems)+len(placed))
+	for _, item := range groceryItems {
+		if _, ok := remaining[item.Id]; ok {
+			reconciled = append(reconciled, models.LayoutBlock{Type: models.GroceryItemId, Value: item.Id})
+		}
+	}
+
+	for i, block := range placed {
+		isEmptySection := block.Type == models.Text && (i+1 == len(placed) || placed[i+1].Type == models.Text)
+		if !isEmptySection {
+			reconciled = append(reconciled, block)
+		}
+	}
+
+	return reconciled
+}
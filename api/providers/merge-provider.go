@@ -0,0 +1,96 @@
+package providers
+
+import (
+	"api/merge"
+	"api/models"
+	"api/proxy"
+	"api/units"
+)
+
+// MergeIngredients adds each ingredient onto the matching item in the list,
+// creating items for the ones that match nothing. It returns the created and
+// the updated items. Ingredients are written in the household's measurement
+// system before they are merged. It runs in a transaction that locks the
+// list, so two imports cannot both decide an ingredient is missing and
+// create it twice, even from different replicas.
+func MergeIngredients(store proxy.Store, householdId string, listId string, ingredients []merge.Ingredient) (created []models.GroceryItem, updated []models.GroceryItem, err error) {
+	err = store.Transaction(func(tx proxy.Store) error {
+		var err error
+		created, updated, err = mergeIngredients(tx, householdId, listId, ingredients)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return created, updated, nil
+}
+
+func mergeIngredients(store proxy.Store, householdId string, listId string, ingredients []merge.Ingredient) ([]models.GroceryItem, []models.GroceryItem, error) {
+	list, err := FindGroceryList(store, householdId, listId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := store.LockGroceryList(list.Id); err != nil {
+		return nil, nil, err
+	}
+
+	household, err := store.GetHousehold(householdId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, err := store.ListGroceryItemsByList(list.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	overrides, err := categoryOverrides(store, householdId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	createdIds := make(map[string]struct{})
+	updatedIndexes := make(map[int]struct{})
+	for _, ingredient := range ingredients {
+		ingredient.Quantity, ingredient.Unit = units.ToSystem(ingredient.Quantity, ingredient.Unit, household.MeasurementSystem)
+
+		i := merge.Find(items, ingredient)
+		if i == -1 {
+			item, err := store.CreateGroceryItem(categorizeGroceryItem(overrides, merge.NewItem(householdId, list.Id, ingredient)))
+			if err != nil {
+				return nil, nil, err
+			}
+			items = append(items, *item)
+			createdIds[item.Id] = struct{}{}
+			continue
+		}
+
+		merged := merge.Add(items[i], ingredient)
+		_, err := store.UpdateGroceryItem(merged.Id, models.GroceryItemPatch{
+			Quantity:        &merged.Quantity,
+			Unit:            &merged.Unit,
+			ExtraQuantities: &merged.ExtraQuantities,
+			Sources:         &merged.Sources,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		items[i] = merged
+		updatedIndexes[i] = struct{}{}
+	}
+
+	// Items are returned as they ended up, so an ingredient created by one
+	// line and added to by another comes back with both amounts
+	var created, updated []models.GroceryItem
+	for i, item := range items {
+		if _, ok := createdIds[item.Id]; ok {
+			created = append(created, item)
+		} else if _, ok := updatedIndexes[i]; ok {
+			updated = append(updated, item)
+		}
+	}
+
+	return created, updated, nil
+}
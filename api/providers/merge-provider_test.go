@@ -0,0 +1,149 @@
+package providers
+
+import (
+	"api/merge"
+	"api/models"
+	"api/proxy"
+	"api/proxy/memory"
+	"api/units"
+	"reflect"
+	"testing"
+)
+
+func newTestHousehold(t *testing.T, store proxy.Store) *models.Household {
+	t.Helper()
+
+	household, err := store.CreateHousehold("Home")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return household
+}
+
+func newTestItem(t *testing.T, store proxy.Store, item models.GroceryItem) *models.GroceryItem {
+	t.Helper()
+
+	if item.Kind == "" {
+		item.Kind = models.GroceryKind
+	}
+	created, err := store.CreateGroceryItem(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return created
+}
+
+// itemsByName returns the list's items keyed by name, failing the test if a
+// name is on it twice
+func itemsByName(t *testing.T, store proxy.Store, listId string) map[string]models.GroceryItem {
+	t.Helper()
+
+	items, err := store.ListGroceryItemsByList(listId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byName := make(map[string]models.GroceryItem, len(items))
+	for _, item := range items {
+		if _, ok := byName[item.Name]; ok {
+			t.Errorf("%s is on the list twice", item.Name)
+		}
+		byName[item.Name] = item
+	}
+
+	return byName
+}
+
+func TestMergeIngredients(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+
+	newTestItem(t, store, models.GroceryItem{HouseholdId: household.Id, Name: "Flour", Quantity: 1, Unit: units.Cup})
+	newTestItem(t, store, models.GroceryItem{HouseholdId: household.Id, Name: "Tomatoes", Quantity: 400, Unit: units.Gram})
+	newTestItem(t, store, models.GroceryItem{HouseholdId: household.Id, Name: "Milk", Checked: true})
+
+	const bread, soup = "https://example.com/bread", "https://example.com/soup"
+	created, updated, err := MergeIngredients(store, household.Id, "", []merge.Ingredient{
+		{Name: "flour", Quantity: 2, Unit: units.Cup, Source: bread},
+		{Name: "milk", Quantity: 1, Unit: units.Cup, Source: bread},
+		{Name: "onions", Quantity: 2, Source: soup},
+		{Name: "tomato", Quantity: 1, Unit: "can", Source: soup},
+		{Name: "onion", Quantity: 1, Source: bread},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := itemsByName(t, store, household.Id)
+	if len(items) != 5 {
+		t.Errorf("got %d items, want the 3 there were and 2 new ones", len(items))
+	}
+
+	if flour := items["Flour"]; flour.Quantity != 3 || flour.Unit != units.Cup || !reflect.DeepEqual(flour.Sources, []string{bread}) {
+		t.Errorf("flour: got %+v, want 3 cups from the bread recipe", flour)
+	}
+
+	tomatoes := items["Tomatoes"]
+	if tomatoes.Quantity != 400 || !reflect.DeepEqual(tomatoes.ExtraQuantities, []models.Quantity{{Amount: 1, Unit: "can"}}) {
+		t.Errorf("tomatoes: got %+v, want 400 grams and a can", tomatoes)
+	}
+
+	// the milk on the list was already bought, so the recipe's goes on anew
+	if milk := items["milk"]; milk.Checked || milk.Quantity != 1 || milk.Category != "Dairy" {
+		t.Errorf("milk: got %+v, want a new unchecked cup of Dairy", milk)
+	}
+
+	if onions := items["onions"]; onions.Quantity != 3 || onions.Category != "Vegetable" || !reflect.DeepEqual(onions.Sources, []string{soup, bread}) {
+		t.Errorf("onions: got %+v, want 3 Vegetable from both recipes", onions)
+	}
+
+	createdNames := make(map[string]models.GroceryItem)
+	for _, item := range created {
+		createdNames[item.Name] = item
+	}
+	if len(created) != 2 || createdNames["onions"].Quantity != 3 || createdNames["milk"].Id == "" {
+		t.Errorf("created: got %+v, want milk and the 3 onions", created)
+	}
+
+	updatedNames := make([]string, len(updated))
+	for i, item := range updated {
+		updatedNames[i] = item.Name
+	}
+	if !reflect.DeepEqual(updatedNames, []string{"Flour", "Tomatoes"}) {
+		t.Errorf("updated: got %q, want Flour and Tomatoes", updatedNames)
+	}
+}
+
+func TestMergeIngredientsInHouseholdSystem(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+	if err := store.SetHouseholdMeasurementSystem(household.Id, models.MetricSystem); err != nil {
+		t.Fatal(err)
+	}
+
+	ingredients := []merge.Ingredient{
+		{Name: "milk", Quantity: 2, Unit: units.Cup},
+		{Name: "butter", Quantity: 2, Unit: units.Pound},
+		{Name: "milk", Quantity: 1, Unit: units.Cup},
+		{Name: "salt", Quantity: 1, Unit: units.Teaspoon},
+	}
+	if _, _, err := MergeIngredients(store, household.Id, "", ingredients); err != nil {
+		t.Fatal(err)
+	}
+
+	items := itemsByName(t, store, household.Id)
+	want := map[string]models.Quantity{
+		// 473 ml for the first 2 cups and 237 ml for the cup after
+		"milk":   {Amount: 710, Unit: units.Milliliter},
+		"butter": {Amount: 907, Unit: units.Gram},
+		"salt":   {Amount: 1, Unit: units.Teaspoon},
+	}
+	for name, quantity := range want {
+		item := items[name]
+		if item.Quantity != quantity.Amount || item.Unit != quantity.Unit {
+			t.Errorf("%s: got %v %s, want %v %s", name, item.Quantity, item.Unit, quantity.Amount, quantity.Unit)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package providers
+
+import (
+	"api/models"
+	"api/prices"
+	"api/proxy"
+	"fmt"
+	"strings"
+)
+
+// ImportPrices validates every price before saving any, so a bad row does not
+// leave half a file imported. Store names lose stray spaces the way item names
+// do, so "Coles " is Coles.
+func ImportPrices(store proxy.Store, storePrices []models.StoreData) (int, error) {
+	for i := range storePrices {
+		storePrices[i].StoreName = models.StorePreference(strings.Join(strings.Fields(string(storePrices[i].StoreName)), " "))
+		if err := storePrices[i].Validate(); err != nil {
+			return 0, fmt.Errorf("price %d: %w", i+1, err)
+		}
+		storePrices[i].ItemName = prices.NormalizeName(storePrices[i].ItemName)
+	}
+
+	if err := store.SaveStorePrices(storePrices); err != nil {
+		return 0, err
+	}
+
+	return len(storePrices), nil
+}
+
+// LookupPrices returns the prices of the catalog item that best matches the
+// item name, cheapest first
+func LookupPrices(store proxy.Store, itemName string) (*models.GroceryItemData, error) {
+	catalog, err := loadCatalog(store)
+	if err != nil {
+		return nil, err
+	}
+
+	name, storePrices, ok := catalog.Lookup(itemName)
+	if !ok {
+		return nil, fmt.Errorf("prices for %s %w", itemName, proxy.ErrNotFound)
+	}
+
+	return &models.GroceryItemData{Name: name, StoreData: storePrices}, nil
+}
+
+func loadCatalog(store proxy.Store) (*prices.Catalog, error) {
+	storePrices, err := store.ListStorePrices()
+	if err != nil {
+		return nil, err
+	}
+
+	return prices.NewCatalog(storePrices), nil
+}
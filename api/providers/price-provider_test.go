@@ -0,0 +1,49 @@
+package providers
+
+import (
+	"api/models"
+	"api/proxy/memory"
+	"testing"
+	"time"
+)
+
+func TestImportPrices(t *testing.T) {
+	store := memory.NewStore()
+	now := time.Now()
+
+	imported, err := ImportPrices(store, []models.StoreData{
+		{ItemName: "Milk", StoreName: "Coles ", Price: 1.60, LastUpdated: now},
+		{ItemName: "🍞 Breads", StoreName: " Coles", Price: 3.00, LastUpdated: now},
+		{ItemName: "milk", StoreName: "Aldi  Express", Price: 1.49, LastUpdated: now},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != 3 {
+		t.Errorf("imported %d prices, want 3", imported)
+	}
+
+	storePrices, err := store.ListStorePrices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stores := make(map[models.StorePreference]int)
+	for _, storePrice := range storePrices {
+		stores[storePrice.StoreName]++
+	}
+	if len(stores) != 2 || stores["Coles"] != 2 || stores["Aldi Express"] != 1 {
+		t.Errorf("got prices at %v, want 2 at Coles and 1 at Aldi Express", stores)
+	}
+
+	// nothing is saved when one price is bad
+	_, err = ImportPrices(store, []models.StoreData{
+		{ItemName: "eggs", StoreName: "Coles", Price: 5.00, LastUpdated: now},
+		{ItemName: "cheese", StoreName: "  ", Price: 6.00, LastUpdated: now},
+	})
+	if err == nil {
+		t.Error("a price without a store was imported")
+	}
+	if _, err := LookupPrices(store, "eggs"); err == nil {
+		t.Error("the prices before the bad one were saved")
+	}
+}
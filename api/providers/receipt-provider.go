@@ -0,0 +1,253 @@
+package providers
+
+import (
+	"api/extract"
+	"api/models"
+	"api/prices"
+	"api/proxy"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrNoReceiptItems is returned for receipts without a line that could be read
+// as an item with its price
+var ErrNoReceiptItems = errors.New("no items with prices found on the receipt")
+
+// receiptHeaderLines is how far down a receipt its store's name is looked for
+const receiptHeaderLines = 6
+
+// ScanReceipt reads a receipt, a PDF or a photo, checks off the household's
+// unchecked groceries that were bought and records what was paid for them at
+// storeName. Each line is matched to at most one item and the other way
+// round, the closest names first. Lines that may have been misread, or that
+// only share words with an item, are left for the user to confirm. When
+// storeName is empty it is looked for at the top of the receipt among the
+// stores the household already uses.
+func ScanReceipt(store proxy.Store, householdId string, storeName models.StorePreference, name string, data []byte) (*models.ReceiptScan, error) {
+	lines, err := receiptText(data)
+	if err != nil {
+		return nil, err
+	}
+
+	receiptLines := prices.ParseReceipt(lines)
+	if len(receiptLines) == 0 {
+		return nil, fmt.Errorf("%s: %w", name, ErrNoReceiptItems)
+	}
+
+	if _, err := GetOrCreateHousehold(store, householdId); err != nil {
+		return nil, fmt.Errorf("Could not get or create household %s: %w", householdId, err)
+	}
+
+	if storeName == "" {
+		storeName, err = receiptStore(store, householdId, lines)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	groceryItems, err := store.ListGroceryItemsByHousehold(householdId)
+	if err != nil {
+		return nil, err
+	}
+
+	scan := &models.ReceiptScan{
+		Store:     storeName,
+		Checked:   make([]models.ReceiptMatch, 0),
+		Unmatched: make([]models.ReceiptLine, 0),
+	}
+
+	matches := matchReceipt(receiptLines, groceryItems)
+	var checked []models.GroceryItem
+	var bought []models.ReceiptItem
+	for i, receiptLine := range receiptLines {
+		item, ok := matches[i]
+		if !ok {
+			scan.Unmatched = append(scan.Unmatched, receiptLine)
+			continue
+		}
+		item.Checked = true
+		scan.Checked = append(scan.Checked, models.ReceiptMatch{ReceiptLine: receiptLine, Item: item})
+		checked = append(checked, item)
+		bought = append(bought, models.ReceiptItem{ItemId: item.Id, Price: receiptLine.Price})
+	}
+
+	if err := recordReceipt(store, storeName, checked, bought); err != nil {
+		return nil, err
+	}
+
+	return scan, nil
+}
+
+// ConfirmReceipt checks off the items the user matched by hand and records
+// their prices
+func ConfirmReceipt(store proxy.Store, request models.ConfirmReceiptRequest) (*models.ConfirmReceiptResponse, error) {
+	groceryItems := make([]models.GroceryItem, len(request.Items))
+	for i, receiptItem := range request.Items {
+		groceryItem, err := store.GetGroceryItem(receiptItem.ItemId)
+		if err != nil {
+			return nil, err
+		}
+		if groceryItem.HouseholdId != request.HouseholdId {
+			return nil, fmt.Errorf("grocery item %w", proxy.ErrNotFound)
+		}
+		groceryItem.Checked = true
+		groceryItems[i] = *groceryItem
+	}
+
+	if err := recordReceipt(store, request.Store, groceryItems, request.Items); err != nil {
+		return nil, err
+	}
+
+	return &models.ConfirmReceiptResponse{Checked: groceryItems}, nil
+}
+
+// recordReceipt checks the items and saves the prices paid, unless the store
+// is Unknown. Both happen in one transaction, so a failed write leaves no item
+// checked without its price.
+func recordReceipt(store proxy.Store, storeName models.StorePreference, groceryItems []models.GroceryItem, bought []models.ReceiptItem) error {
+	return store.Transaction(func(tx proxy.Store) error {
+		storePrices := make([]models.StoreData, 0, len(groceryItems))
+		now := time.Now()
+		for i, groceryItem := range groceryItems {
+			if err := tx.UpdateGroceryItemStatus(groceryItem.Id, true); err != nil {
+				return err
+			}
+			storePrices = append(storePrices, models.StoreData{
+				ItemName:    prices.NormalizeName(groceryItem.Name),
+				Price:       bought[i].Price,
+				LastUpdated: now,
+				StoreName:   storeName,
+			})
+		}
+
+		if storeName == models.Unknown || len(storePrices) == 0 {
+			return nil
+		}
+
+		return tx.SaveStorePrices(storePrices)
+	})
+}
+
+// receiptText reads the lines of a PDF receipt, as emailed by some stores, or
+// of a photo of a printed one
+func receiptText(data []byte) ([]extract.OCRLine, error) {
+	pages, err := extract.PDFText(data)
+	if errors.Is(err, extract.ErrNotPDF) {
+		return extract.ImageText(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []extract.OCRLine
+	for _, page := range pages {
+		for _, text := range strings.Split(page, "\n") {
+			lines = append(lines, extract.OCRLine{Text: text, Confidence: 100})
+		}
+	}
+	return lines, nil
+}
+
+// receiptStore finds which store is named at the top of the receipt, out of
+// those with prices and those in the household's rules and history
+func receiptStore(store proxy.Store, householdId string, lines []extract.OCRLine) (models.StorePreference, error) {
+	stores, err := knownStores(store, householdId, nil)
+	if err != nil {
+		return "", err
+	}
+
+	choices, err := store.ListStoreHistory(householdId)
+	if err != nil {
+		return "", err
+	}
+	for _, choice := range choices {
+		stores[choice.Store] = true
+	}
+
+	var header strings.Builder
+	for _, line := range lines[:min(receiptHeaderLines, len(lines))] {
+		header.WriteString(" " + line.Text)
+	}
+	headerWords := receiptWords(header.String())
+
+	// longest first, so "aldi express" wins over "aldi"
+	names := make([]string, 0, len(stores))
+	for storeName := range stores {
+		if storeName != models.Unknown && strings.TrimSpace(string(storeName)) != "" {
+			names = append(names, string(storeName))
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) > len(names[j])
+		}
+		return names[i] < names[j]
+	})
+
+	for _, storeName := range names {
+		if words := receiptWords(storeName); strings.TrimSpace(words) != "" && strings.Contains(headerWords, words) {
+			return models.StorePreference(storeName), nil
+		}
+	}
+
+	return models.Unknown, nil
+}
+
+// receiptWords is the text's words in lower case, with a space either side of
+// each, so "ALDI Stores." contains " aldi " but not " ald "
+func receiptWords(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return " " + strings.Join(words, " ") + " "
+}
+
+// matchReceipt pairs receipt lines with unchecked groceries, keyed by the
+// index of the line. The most similar pairs are taken first, so "almond
+// milk" goes to the almond milk on the list and not to the milk. Names are
+// only compared by their letters, so "milk" isn't taken to be "oat milk", and
+// lines to review aren't matched.
+func matchReceipt(receiptLines []models.ReceiptLine, groceryItems []models.GroceryItem) map[int]models.GroceryItem {
+	type candidate struct {
+		line  int
+		item  int
+		score float64
+	}
+
+	var candidates []candidate
+	for i, receiptLine := range receiptLines {
+		if receiptLine.Review {
+			continue
+		}
+		lineName := prices.NormalizeName(receiptLine.Name)
+		for j, groceryItem := range groceryItems {
+			if groceryItem.Checked || groceryItem.Kind == models.TaskKind {
+				continue
+			}
+			if score := prices.LetterSimilarity(lineName, prices.NormalizeName(groceryItem.Name)); score >= prices.MatchThreshold {
+				candidates = append(candidates, candidate{line: i, item: j, score: score})
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	matches := make(map[int]models.GroceryItem)
+	matchedItems := make(map[int]bool)
+	for _, candidate := range candidates {
+		if _, ok := matches[candidate.line]; ok || matchedItems[candidate.item] {
+			continue
+		}
+		matches[candidate.line] = groceryItems[candidate.item]
+		matchedItems[candidate.item] = true
+	}
+
+	return matches
+}
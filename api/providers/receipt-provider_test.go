@@ -0,0 +1,174 @@
+package providers
+
+import (
+	"api/extract"
+	"api/models"
+	"api/proxy"
+	"api/proxy/memory"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// failingPricesStore can't save prices, as if the database went away after
+// the items were checked
+type failingPricesStore struct {
+	proxy.Store
+}
+
+func (store failingPricesStore) Transaction(fn func(tx proxy.Store) error) error {
+	return store.Store.Transaction(func(tx proxy.Store) error {
+		return fn(failingPricesStore{tx})
+	})
+}
+
+func (store failingPricesStore) SaveStorePrices(storePrices []models.StoreData) error {
+	return errCrash
+}
+
+func TestScanReceipt(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+	for _, name := range []string{"Bananas", "Skim milk", "Milk", "Sourdough bread", "Apples", "Eggs"} {
+		newTestItem(t, store, models.GroceryItem{HouseholdId: household.Id, Name: name})
+	}
+
+	scan, err := ScanReceipt(store, household.Id, "Aldi", "receipt.pdf", readTestPDF(t, "receipt.pdf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checked := make(map[string]float64)
+	for _, match := range scan.Checked {
+		checked[match.Item.Name] = match.ReceiptLine.Price
+	}
+	wantChecked := map[string]float64{"Bananas": 3.50, "Skim milk": 1.49, "Sourdough bread": 4.29}
+	if !reflect.DeepEqual(checked, wantChecked) {
+		t.Errorf("checked %v, want %v", checked, wantChecked)
+	}
+
+	// The full cream milk only shares a word with the milk on the list
+	if len(scan.Unmatched) != 1 || scan.Unmatched[0].Name != "FULL CREAM MILK" {
+		t.Errorf("unmatched %+v, want the full cream milk", scan.Unmatched)
+	}
+
+	items := itemsByName(t, store, household.Id)
+	for name, item := range items {
+		if _, bought := wantChecked[name]; item.Checked != bought {
+			t.Errorf("%s: got checked %v, want %v", name, item.Checked, bought)
+		}
+	}
+
+	storePrices, err := store.ListStorePrices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := make(map[string]float64)
+	for _, storePrice := range storePrices {
+		if storePrice.StoreName != "Aldi" {
+			t.Errorf("%s: recorded at %s, want Aldi", storePrice.ItemName, storePrice.StoreName)
+		}
+		recorded[storePrice.ItemName] = storePrice.Price
+	}
+	wantRecorded := map[string]float64{"banana": 3.50, "skim milk": 1.49, "sourdough bread": 4.29}
+	if !reflect.DeepEqual(recorded, wantRecorded) {
+		t.Errorf("recorded %v, want %v", recorded, wantRecorded)
+	}
+}
+
+func TestScanReceiptWithoutItems(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+
+	_, err := ScanReceipt(store, household.Id, "Aldi", "receipt-totals.pdf", readTestPDF(t, "receipt-totals.pdf"))
+	if !errors.Is(err, ErrNoReceiptItems) {
+		t.Errorf("got %v, want ErrNoReceiptItems", err)
+	}
+}
+
+func TestMatchReceipt(t *testing.T) {
+	receiptLines := []models.ReceiptLine{
+		{Name: "ALMOND MILK", Price: 2.50},
+		{Name: "OAT MILK BARISTA", Price: 3.20},
+		{Name: "BUTTER", Price: 4.00, Review: true},
+		{Name: "MILK", Price: 1.99},
+	}
+	groceryItems := []models.GroceryItem{
+		{Id: "milk", Name: "Milk"},
+		{Id: "almond-milk", Name: "Almond milk"},
+		{Id: "oat-milk", Name: "Oat milk"},
+		{Id: "butter", Name: "Butter"},
+	}
+
+	// The barista oat milk only contains the name of the oat milk, and a line
+	// that may have been misread is left to the user even when it reads
+	// exactly as an item
+	got := make(map[int]string)
+	for line, item := range matchReceipt(receiptLines, groceryItems) {
+		got[line] = item.Id
+	}
+	want := map[int]string{0: "almond-milk", 3: "milk"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestScanReceiptFailsWhole(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+	bananas := newTestItem(t, store, models.GroceryItem{HouseholdId: household.Id, Name: "Bananas"})
+
+	_, err := ScanReceipt(failingPricesStore{store}, household.Id, "Aldi", "receipt.pdf", readTestPDF(t, "receipt.pdf"))
+	if !errors.Is(err, errCrash) {
+		t.Fatalf("got %v, want %v", err, errCrash)
+	}
+	_, err = ConfirmReceipt(failingPricesStore{store}, models.ConfirmReceiptRequest{
+		HouseholdId: household.Id,
+		Store:       "Aldi",
+		Items:       []models.ReceiptItem{{ItemId: bananas.Id, Price: 3.50}},
+	})
+	if !errors.Is(err, errCrash) {
+		t.Fatalf("confirming: got %v, want %v", err, errCrash)
+	}
+
+	// the items are not checked when their prices could not be saved
+	if item := itemsByName(t, store, household.Id)["Bananas"]; item.Checked {
+		t.Error("the bananas were checked without their price")
+	}
+}
+
+func TestReceiptStore(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+	for _, storeName := range []models.StorePreference{"Ald", "Aldi", "Aldi Express", "IGA"} {
+		if _, err := SaveStoreRule(store, models.StoreRule{HouseholdId: household.Id, Category: string(storeName), Store: storeName}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		header []string
+		want   models.StorePreference
+	}{
+		{[]string{"ALDI STORES", "12 Main Street"}, "Aldi"},
+		{[]string{"Welcome to", "ALDI  express."}, "Aldi Express"},
+		{[]string{"*** IGA ***"}, "IGA"},
+		{[]string{"ALDIS", "12 Main Street"}, models.Unknown},
+		{[]string{"Corner Store", "1", "2", "3", "4", "5", "ALDI"}, models.Unknown},
+	}
+
+	for _, tt := range tests {
+		lines := make([]extract.OCRLine, len(tt.header))
+		for i, text := range tt.header {
+			lines[i] = extract.OCRLine{Text: text, Confidence: 100}
+		}
+
+		got, err := receiptStore(store, household.Id, lines)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("receiptStore(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
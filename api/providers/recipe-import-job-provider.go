@@ -0,0 +1,114 @@
+package providers
+
+import (
+	"api/models"
+	"api/proxy"
+	"errors"
+)
+
+// QueueRecipeImport records a job for the workers in api/jobs to pick up
+func QueueRecipeImport(store proxy.Store, request models.RecipeImportRequest) (*models.RecipeImportJob, error) {
+	list, err := FindGroceryList(store, request.HouseholdId, request.ListId)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.CreateRecipeImportJob(models.RecipeImportJob{
+		HouseholdId: list.HouseholdId,
+		ListId:      list.Id,
+		ItemId:      request.ItemId,
+		Url:         request.Url,
+		Servings:    request.Servings,
+	})
+}
+
+// QueueRecipeImports queues a job per recipe url in place of ImportRecipes,
+// reporting each one as Queued. itemIds are the list items the urls came from.
+func QueueRecipeImports(store proxy.Store, householdId string, listId string, recipeUrls []string, itemIds []string, servings float64) (*RecipeImport, error) {
+	results := make([]models.RecipeImportResult, len(recipeUrls))
+	for i, recipeUrl := range recipeUrls {
+		job, err := QueueRecipeImport(store, models.RecipeImportRequest{
+			HouseholdId: householdId,
+			ListId:      listId,
+			Url:         recipeUrl,
+			ItemId:      itemIds[i],
+			Servings:    servings,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		results[i] = models.RecipeImportResult{Url: recipeUrl, Status: models.RecipeQueued, JobId: job.Id, ItemId: itemIds[i]}
+	}
+
+	return &RecipeImport{Results: results}, nil
+}
+
+func GetRecipeImportJob(store proxy.Store, id string) (*models.RecipeImportJob, error) {
+	return store.GetRecipeImportJob(id)
+}
+
+// RunRecipeImportJob imports the job's recipe and marks it Done. As with
+// GroceryMagic the url item is only removed once the ingredients are on the
+// list. The recipe is downloaded first, then its ingredients are merged, the
+// url item removed and the job finished in one transaction, so a job that
+// stops part way, e.g. in a restart, changes nothing and is run again in full
+// once its lease runs out. A job whose lease was lost to another worker
+// returns proxy.ErrLeaseLost without changing anything either.
+func RunRecipeImportJob(store proxy.Store, job models.RecipeImportJob) (*models.RecipeImportJob, error) {
+	recipes, err := downloadRecipes(store, job.HouseholdId, []string{job.Url}, job.Servings)
+	if err != nil {
+		return nil, err
+	}
+
+	err = store.Transaction(func(tx proxy.Store) error {
+		// Renewing checks the lease before anything is changed, and keeps
+		// another worker from claiming the job until the transaction ends
+		if err := tx.RenewRecipeImportJob(job); err != nil {
+			return err
+		}
+
+		recipeImport, err := addRecipes(tx, job.HouseholdId, job.ListId, []string{job.Url}, recipes)
+		if err != nil {
+			return err
+		}
+
+		result := recipeImport.Results[0]
+		job.Status = result.Status
+		job.Ingredients = result.Ingredients
+		job.Error = result.Error
+		job.CreatedItemIds = make([]string, len(recipeImport.Created))
+		for i, item := range recipeImport.Created {
+			job.CreatedItemIds[i] = item.Id
+		}
+		job.UpdatedItemIds = make([]string, len(recipeImport.Updated))
+		for i, item := range recipeImport.Updated {
+			job.UpdatedItemIds[i] = item.Id
+		}
+
+		if result.Status == models.RecipeImported && job.ItemId != "" {
+			if err := deleteRecipeItem(tx, job.HouseholdId, job.ItemId); err != nil {
+				return err
+			}
+		}
+
+		return tx.FinishRecipeImportJob(job)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	job.State = models.JobDone
+	return &job, nil
+}
+
+// deleteRecipeItem removes the url item a recipe was imported from. The user
+// may have removed it already, which leaves nothing to do.
+func deleteRecipeItem(store proxy.Store, householdId string, itemId string) error {
+	err := DeleteGroceryItem(store, householdId, itemId)
+	if errors.Is(err, proxy.ErrNotFound) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,117 @@
+package providers
+
+import (
+	"api/models"
+	"api/proxy"
+	"api/proxy/memory"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errCrash = errors.New("crashed")
+
+// crashingStore fails to finish jobs, as if the worker stopped after merging
+// the ingredients
+type crashingStore struct {
+	proxy.Store
+}
+
+func (store crashingStore) Transaction(fn func(tx proxy.Store) error) error {
+	return store.Store.Transaction(func(tx proxy.Store) error {
+		return fn(crashingStore{tx})
+	})
+}
+
+func (store crashingStore) FinishRecipeImportJob(job models.RecipeImportJob) error {
+	return errCrash
+}
+
+func TestRunRecipeImportJobAgain(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+	soup := newRecipeServer(t, 0, "Soup", "2 onions", "1 cup chicken broth")
+	urlItem := newTestItem(t, store, models.GroceryItem{HouseholdId: household.Id, Name: soup.URL})
+
+	_, err := QueueRecipeImport(store, models.RecipeImportRequest{HouseholdId: household.Id, Url: soup.URL, ItemId: urlItem.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := store.ClaimRecipeImportJob(time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := RunRecipeImportJob(crashingStore{store}, *job); !errors.Is(err, errCrash) {
+		t.Fatalf("crashed run: got %v, want %v", err, errCrash)
+	}
+	if items := itemsByName(t, store, household.Id); len(items) != 1 {
+		t.Errorf("crashed run left %d items, want only the url", len(items))
+	}
+
+	// Once the lease runs out the job is claimed again, and the first worker
+	// can no longer finish it
+	reclaimed, err := store.ClaimRecipeImportJob(time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RunRecipeImportJob(store, *job); !errors.Is(err, proxy.ErrLeaseLost) {
+		t.Errorf("run with a lost lease: got %v, want ErrLeaseLost", err)
+	}
+
+	done, err := RunRecipeImportJob(store, *reclaimed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.State != models.JobDone || done.Status != models.RecipeImported || len(done.CreatedItemIds) != 2 {
+		t.Errorf("run after the crash: got %+v", *done)
+	}
+
+	items := itemsByName(t, store, household.Id)
+	if len(items) != 2 || items["onion"].Quantity != 2 {
+		t.Errorf("after the crash and the lost lease: got %+v, want 2 onions and chicken broth", items)
+	}
+	if _, ok := items[soup.URL]; ok {
+		t.Error("the url item was not removed")
+	}
+}
+
+// TestRunRecipeImportJobWithoutItem runs a job whose url item the user
+// removed while it was queued
+func TestRunRecipeImportJobWithoutItem(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+	soup := newRecipeServer(t, 0, "Soup", "2 onions", "1 cup chicken broth")
+	urlItem := newTestItem(t, store, models.GroceryItem{HouseholdId: household.Id, Name: soup.URL})
+
+	_, err := QueueRecipeImport(store, models.RecipeImportRequest{HouseholdId: household.Id, Url: soup.URL, ItemId: urlItem.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteGroceryItem(store, household.Id, urlItem.Id); err != nil {
+		t.Fatal(err)
+	}
+	job, err := store.ClaimRecipeImportJob(time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done, err := RunRecipeImportJob(store, *job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.State != models.JobDone || done.Status != models.RecipeImported {
+		t.Errorf("got %s %s, want Done and Imported", done.State, done.Status)
+	}
+
+	saved, err := store.GetRecipeImportJob(job.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.State != models.JobDone {
+		t.Errorf("stored job is %s, want Done", saved.State)
+	}
+	if items := itemsByName(t, store, household.Id); len(items) != 2 || items["onion"].Quantity != 2 {
+		t.Errorf("got %+v, want 2 onions and chicken broth", items)
+	}
+}
@@ -0,0 +1,236 @@
+package providers
+
+import (
+	"api/merge"
+	"api/models"
+	"api/parsing"
+	"api/proxy"
+	"errors"
+	"sync"
+)
+
+// maxConcurrentRecipeFetches bounds how many recipe pages are downloaded at once
+const maxConcurrentRecipeFetches = 4
+
+// fetchedRecipe is a downloaded recipe, tagged with its position in the request
+type fetchedRecipe struct {
+	index       int
+	ingredients []merge.Ingredient
+	servings    float64
+	scale       float64
+	// save is the recipe as parsed, before scaling, to add to the library
+	save *models.SavedRecipe
+	err  error
+}
+
+// RecipeImport is the outcome of ImportRecipes
+type RecipeImport struct {
+	Created []models.GroceryItem
+	Updated []models.GroceryItem
+	// Results line up with the recipe urls
+	Results []models.RecipeImportResult
+}
+
+// ImportRecipes adds the ingredients of every recipe to the list. Recipes are
+// downloaded concurrently but merged one after another in the order given, so
+// when two recipes share an ingredient the same one always creates the item
+// and the other adds to it. A recipe that fails is reported in its result and
+// does not stop the others; only store errors are returned. Recipes are scaled
+// to feed servings when it is above 0 and they say how many they feed.
+// Recipes already in the household's library are not downloaded again, their
+// saved ingredients are used, and new ones are saved. itemIds are the list
+// items the urls came from, and those of imported recipes are removed along
+// with adding their ingredients.
+func ImportRecipes(store proxy.Store, householdId string, listId string, recipeUrls []string, itemIds []string, servings float64) (*RecipeImport, error) {
+	recipes, err := downloadRecipes(store, householdId, recipeUrls, servings)
+	if err != nil {
+		return nil, err
+	}
+
+	var recipeImport *RecipeImport
+	err = store.Transaction(func(tx proxy.Store) error {
+		var err error
+		recipeImport, err = addRecipes(tx, householdId, listId, recipeUrls, recipes)
+		if err != nil {
+			return err
+		}
+
+		for i, result := range recipeImport.Results {
+			recipeImport.Results[i].ItemId = itemIds[i]
+			if result.Status == models.RecipeImported && itemIds[i] != "" {
+				if err := deleteRecipeItem(tx, householdId, itemIds[i]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return recipeImport, nil
+}
+
+// downloadRecipes fetches the recipes, using the household's library for the
+// ones saved in it
+func downloadRecipes(store proxy.Store, householdId string, recipeUrls []string, servings float64) ([]fetchedRecipe, error) {
+	library, err := savedRecipesByUrl(store, householdId)
+	if err != nil {
+		return nil, err
+	}
+
+	return fetchRecipes(recipeUrls, servings, library), nil
+}
+
+// addRecipes merges the downloaded recipes into the list in order and saves
+// the new ones to the library. It is meant to run in a transaction.
+func addRecipes(store proxy.Store, householdId string, listId string, recipeUrls []string, recipes []fetchedRecipe) (*RecipeImport, error) {
+	// The library is read again as another import may have saved one of the
+	// recipes while they were downloaded
+	library, err := savedRecipesByUrl(store, householdId)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.RecipeImportResult, len(recipes))
+
+	var order []string
+	latest := make(map[string]models.GroceryItem)
+	wasCreated := make(map[string]bool)
+	for i, recipe := range recipes {
+		results[i] = recipeImportResult(recipeUrls[i], recipe)
+		if results[i].Status != models.RecipeImported {
+			continue
+		}
+
+		created, updated, err := MergeIngredients(store, householdId, listId, recipe.ingredients)
+		if err != nil {
+			return nil, err
+		}
+
+		// The same url twice in one request is only saved once
+		if recipe.save != nil && library[recipe.save.SourceUrl] == nil {
+			recipe.save.HouseholdId = householdId
+			if library[recipe.save.SourceUrl], err = store.CreateSavedRecipe(*recipe.save); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, item := range created {
+			wasCreated[item.Id] = true
+		}
+		for _, item := range append(created, updated...) {
+			if _, ok := latest[item.Id]; !ok {
+				order = append(order, item.Id)
+			}
+			latest[item.Id] = item
+		}
+	}
+
+	// An item created by one recipe and added to by a later one is reported
+	// once, as created, with the later quantities
+	var createdItems, updatedItems []models.GroceryItem
+	for _, id := range order {
+		if wasCreated[id] {
+			createdItems = append(createdItems, latest[id])
+		} else {
+			updatedItems = append(updatedItems, latest[id])
+		}
+	}
+
+	return &RecipeImport{Created: createdItems, Updated: updatedItems, Results: results}, nil
+}
+
+func recipeImportResult(recipeUrl string, recipe fetchedRecipe) models.RecipeImportResult {
+	result := models.RecipeImportResult{
+		Url:         recipeUrl,
+		Ingredients: len(recipe.ingredients),
+		Servings:    recipe.servings,
+		Scale:       recipe.scale,
+	}
+
+	switch {
+	case errors.Is(recipe.err, parsing.ErrFetch):
+		result.Status = models.RecipeFetchFailed
+	case recipe.err != nil:
+		result.Status = models.RecipeParseFailed
+	case len(recipe.ingredients) == 0:
+		result.Status = models.RecipeNoIngredients
+	default:
+		result.Status = models.RecipeImported
+	}
+
+	if recipe.err != nil {
+		result.Error = recipe.err.Error()
+	}
+
+	return result
+}
+
+// fetchRecipes downloads and parses the recipes on a bounded pool of workers
+// and returns them in the order of recipeUrls. The library is only read.
+func fetchRecipes(recipeUrls []string, servings float64, library map[string]*models.SavedRecipe) []fetchedRecipe {
+	indexes := make(chan int)
+	results := make(chan fetchedRecipe)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < min(maxConcurrentRecipeFetches, len(recipeUrls)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results <- fetchRecipe(i, recipeUrls[i], servings, library[recipeUrls[i]])
+			}
+		}()
+	}
+
+	go func() {
+		for i := range recipeUrls {
+			indexes <- i
+		}
+		close(indexes)
+		wg.Wait()
+		close(results)
+	}()
+
+	recipes := make([]fetchedRecipe, len(recipeUrls))
+	for result := range results {
+		recipes[result.index] = result
+	}
+
+	return recipes
+}
+
+func fetchRecipe(index int, recipeUrl string, servings float64, saved *models.SavedRecipe) fetchedRecipe {
+	if saved != nil {
+		fetched := fetchedRecipe{index: index, servings: saved.Servings}
+		fetched.ingredients, fetched.scale = savedRecipeIngredients(*saved, servings)
+		return fetched
+	}
+
+	recipe, err := parsing.NewFromURL(recipeUrl)
+	if err != nil {
+		return fetchedRecipe{index: index, err: err}
+	}
+
+	fetched := fetchedRecipe{index: index, servings: recipe.Servings}
+	fetched.save = &models.SavedRecipe{
+		Title:       recipe.Name,
+		SourceUrl:   recipeUrl,
+		Servings:    recipe.Servings,
+		Ingredients: recipeIngredients(recipe),
+	}
+	if fetched.save.Title == "" {
+		fetched.save.Title = recipeUrl
+	}
+	fetched.scale = recipe.Scale(servings)
+
+	parsedIngredients := recipe.IngredientList().Ingredients
+	fetched.ingredients = make([]merge.Ingredient, len(parsedIngredients))
+	for i, ingredient := range parsedIngredients {
+		fetched.ingredients[i] = merge.FromParsed(ingredient, recipeUrl)
+	}
+
+	return fetched
+}
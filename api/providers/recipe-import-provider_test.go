@@ -0,0 +1,167 @@
+package providers
+
+import (
+	"api/models"
+	"api/proxy/memory"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recipeServer serves a recipe page with the ingredients after a delay, and
+// counts how often it was asked for it
+type recipeServer struct {
+	*httptest.Server
+	requests atomic.Int32
+}
+
+func newRecipeServer(t *testing.T, delay time.Duration, name string, ingredients ...string) *recipeServer {
+	t.Helper()
+
+	recipe, err := json.Marshal(map[string]any{
+		"@context":         "https://schema.org",
+		"@type":            "Recipe",
+		"name":             name,
+		"recipeIngredient": ingredients,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := fmt.Sprintf(`<html><head><title>%s</title><script type="application/ld+json">%s</script></head><body></body></html>`, name, recipe)
+
+	server := &recipeServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.requests.Add(1)
+		time.Sleep(delay)
+		fmt.Fprint(w, page)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// TestImportRecipes imports more recipes than there are workers, the earlier
+// ones slower than the later ones, so they finish in a different order from
+// the one they were asked for in
+func TestImportRecipes(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+
+	soup := newRecipeServer(t, 300*time.Millisecond, "Soup", "2 onions", "1 cup chicken broth")
+	bread := newRecipeServer(t, 150*time.Millisecond, "Bread", "2 cups flour", "1 onion")
+	pancakes := newRecipeServer(t, 0, "Pancakes", "1 cup flour", "3 eggs", "1 cup milk")
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(down.Close)
+	gone := httptest.NewServer(http.NotFoundHandler())
+	gone.Close()
+	empty := newRecipeServer(t, 0, "Nothing")
+
+	recipeUrls := []string{soup.URL, bread.URL, down.URL, pancakes.URL, gone.URL, empty.URL}
+	imported, err := ImportRecipes(store, household.Id, "", recipeUrls, make([]string, len(recipeUrls)), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantStatuses := []models.RecipeImportStatus{
+		models.RecipeImported,
+		models.RecipeImported,
+		models.RecipeFetchFailed,
+		models.RecipeImported,
+		models.RecipeFetchFailed,
+		models.RecipeNoIngredients,
+	}
+	if len(imported.Results) != len(recipeUrls) {
+		t.Fatalf("got %d results for %d urls", len(imported.Results), len(recipeUrls))
+	}
+	for i, result := range imported.Results {
+		if result.Url != recipeUrls[i] || result.Status != wantStatuses[i] {
+			t.Errorf("result %d: got %s %s, want %s %s", i, result.Url, result.Status, recipeUrls[i], wantStatuses[i])
+		}
+	}
+
+	// The soup finished last but was asked for first, so it creates the onions
+	// and the bread adds to them
+	wantCreated := []string{"onion", "chicken broth", "flour", "egg", "milk"}
+	if names := itemNames(imported.Created); !reflect.DeepEqual(names, wantCreated) {
+		t.Errorf("created %v, want %v", names, wantCreated)
+	}
+	if len(imported.Updated) != 0 {
+		t.Errorf("updated %v, want nothing", itemNames(imported.Updated))
+	}
+
+	items := itemsByName(t, store, household.Id)
+	onion := items["onion"]
+	if onion.Quantity != 3 || !reflect.DeepEqual(onion.Sources, []string{soup.URL, bread.URL}) {
+		t.Errorf("onion: got %v from %v, want 3 from the soup then the bread", onion.Quantity, onion.Sources)
+	}
+	flour := items["flour"]
+	if flour.Quantity != 3 || !reflect.DeepEqual(flour.Sources, []string{bread.URL, pancakes.URL}) {
+		t.Errorf("flour: got %v %s from %v, want 3 cups from the bread then the pancakes", flour.Quantity, flour.Unit, flour.Sources)
+	}
+
+	library, err := store.ListSavedRecipes(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(library) != 3 {
+		t.Errorf("saved %d recipes, want the 3 imported", len(library))
+	}
+
+	// Saved recipes are not downloaded again
+	if _, err := ImportRecipes(store, household.Id, "", []string{soup.URL, bread.URL}, []string{"", ""}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if soup.requests.Load() != 1 || bread.requests.Load() != 1 {
+		t.Errorf("got %d soup and %d bread requests, want 1 each", soup.requests.Load(), bread.requests.Load())
+	}
+	if onion := itemsByName(t, store, household.Id)["onion"]; onion.Quantity != 6 {
+		t.Errorf("onion after importing again: got %v, want 6", onion.Quantity)
+	}
+}
+
+// TestImportRecipesRemovesUrlItems replaces the url items of imported recipes
+// with their ingredients, and keeps the ones that failed
+func TestImportRecipesRemovesUrlItems(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+
+	soup := newRecipeServer(t, 0, "Soup", "2 onions")
+	bread := newRecipeServer(t, 0, "Bread", "2 cups flour")
+	gone := httptest.NewServer(http.NotFoundHandler())
+	gone.Close()
+
+	soupItem := newTestItem(t, store, models.GroceryItem{HouseholdId: household.Id, Name: soup.URL})
+	goneItem := newTestItem(t, store, models.GroceryItem{HouseholdId: household.Id, Name: gone.URL})
+	// the bread's item was removed by the user while the page downloaded
+	recipeUrls := []string{soup.URL, gone.URL, bread.URL}
+	itemIds := []string{soupItem.Id, goneItem.Id, "removed"}
+
+	imported, err := ImportRecipes(store, household.Id, "", recipeUrls, itemIds, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, result := range imported.Results {
+		if result.ItemId != itemIds[i] {
+			t.Errorf("result %d: got item %q, want %q", i, result.ItemId, itemIds[i])
+		}
+	}
+
+	items := itemsByName(t, store, household.Id)
+	if _, ok := items[soup.URL]; ok {
+		t.Error("the imported recipe's url item was not removed")
+	}
+	if _, ok := items[gone.URL]; !ok {
+		t.Error("the failed recipe's url item was removed")
+	}
+	if len(items) != 3 {
+		t.Errorf("got %d items, want the onions, the flour and the failed url", len(items))
+	}
+}
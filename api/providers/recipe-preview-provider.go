@@ -0,0 +1,167 @@
+package providers
+
+import (
+	"api/extract"
+	"api/merge"
+	"api/models"
+	"api/parsing"
+	"api/proxy"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// PreviewRecipeText parses a pasted recipe without adding anything to a list
+func PreviewRecipeText(text string, servings float64) (*models.RecipePreview, error) {
+	recipe, err := parsing.NewFromText("pasted recipe", text)
+	if err != nil {
+		return nil, err
+	}
+
+	return previewRecipe(recipe, servings), nil
+}
+
+// PreviewRecipePDF parses the text of a recipe PDF, such as a cookbook export
+// or a printed web page
+func PreviewRecipePDF(name string, data []byte, servings float64) (*models.RecipePreview, error) {
+	pages, err := extract.PDFText(data)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.Join(pages, "\n")
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("%s has no text to read, it may be a scan", name)
+	}
+
+	recipe, err := parsing.NewFromText(name, text)
+	if err != nil {
+		return nil, err
+	}
+
+	return previewRecipe(recipe, servings), nil
+}
+
+// PreviewRecipeImage reads a photo of a recipe, such as a cookbook page or a
+// handwritten card. Ingredients on lines Tesseract wasn't confident about are
+// marked for review.
+func PreviewRecipeImage(name string, data []byte, servings float64) (*models.RecipePreview, error) {
+	lines, err := extract.ImageText(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return previewRecipeLines(name, extract.CleanRecipeLines(lines), servings)
+}
+
+// previewRecipeLines previews the lines read from an image, marking the
+// ingredients read from lines below extract.LowConfidence for review
+func previewRecipeLines(name string, lines []extract.OCRLine, servings float64) (*models.RecipePreview, error) {
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("no text found in %s", name)
+	}
+
+	text := make([]string, len(lines))
+	for i, line := range lines {
+		text[i] = line.Text
+	}
+
+	recipe, err := parsing.NewFromText(name, strings.Join(text, "\n"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Ingredients are listed in the order of the lines they were read from, so
+	// each is matched to the next line it was cleaned from
+	preview := previewRecipe(recipe, servings)
+	next := 0
+	for i, ingredient := range preview.Ingredients {
+		for j := next; j < len(lines); j++ {
+			if parsing.CleanTextLine(lines[j].Text) == ingredient.Line {
+				preview.Ingredients[i].Review = lines[j].Confidence < extract.LowConfidence
+				next = j + 1
+				break
+			}
+		}
+	}
+
+	return preview, nil
+}
+
+// previewRecipe scales the recipe and lists its ingredients line by line, so
+// the same ingredient on two lines can be corrected on each
+func previewRecipe(recipe *parsing.Recipe, servings float64) *models.RecipePreview {
+	preview := &models.RecipePreview{
+		Name:     recipe.Name,
+		Servings: recipe.Servings,
+		Skipped:  append(make([]string, 0), recipe.Skipped...),
+	}
+	preview.Scale = recipe.Scale(servings)
+	preview.Ingredients = recipeIngredients(recipe)
+
+	return preview
+}
+
+func recipeIngredients(recipe *parsing.Recipe) []models.RecipeIngredient {
+	parsedIngredients := recipe.IngredientList().Ingredients
+	ingredients := make([]models.RecipeIngredient, len(parsedIngredients))
+	for i, ingredient := range parsedIngredients {
+		ingredients[i] = models.RecipeIngredient{
+			Line:     ingredient.Line,
+			Name:     ingredient.Name,
+			Quantity: ingredient.Measure.Amount,
+			Unit:     parsing.NormalizeMeasureName(ingredient.Measure.Name),
+		}
+	}
+
+	return ingredients
+}
+
+// AddRecipeIngredients merges previewed ingredients into the list
+func AddRecipeIngredients(store proxy.Store, request models.AddIngredientsRequest) (*models.AddIngredientsResponse, error) {
+	ingredients := mergeRecipeIngredients(request.Ingredients, request.Source, 1)
+
+	created, updated, err := MergeIngredients(store, request.HouseholdId, request.ListId, ingredients)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AddIngredientsResponse{
+		Created: append(make([]models.GroceryItem, 0), created...),
+		Updated: append(make([]models.GroceryItem, 0), updated...),
+	}, nil
+}
+
+// mergeRecipeIngredients multiplies the quantities by scale, rounded the way
+// parsed recipes are when they are scaled
+func mergeRecipeIngredients(recipeIngredients []models.RecipeIngredient, source string, scale float64) []merge.Ingredient {
+	ingredients := make([]merge.Ingredient, len(recipeIngredients))
+	for i, ingredient := range recipeIngredients {
+		quantity := ingredient.Quantity
+		if scale != 1 {
+			quantity = math.Round(quantity*scale*100) / 100
+		}
+		ingredients[i] = merge.Ingredient{
+			Name:     ingredient.Name,
+			Quantity: quantity,
+			Unit:     ingredientUnit(ingredient.Unit),
+			Source:   source,
+		}
+	}
+
+	return ingredients
+}
+
+// ingredientUnit normalizes a unit from a preview, keeping ones the parser
+// doesn't know, like "pinch", as they were typed
+func ingredientUnit(unit string) string {
+	if normalized := parsing.NormalizeMeasureName(unit); normalized != "" {
+		return normalized
+	}
+
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	if unit == "whole" {
+		return ""
+	}
+	return unit
+}
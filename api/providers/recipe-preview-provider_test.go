@@ -0,0 +1,93 @@
+package providers
+
+import (
+	"api/extract"
+	"api/models"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func readTestPDF(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func TestPreviewRecipePDF(t *testing.T) {
+	preview, err := PreviewRecipePDF("pancakes.pdf", readTestPDF(t, "pancakes.pdf"), 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if preview.Servings != 4 || preview.Scale != 2 {
+		t.Errorf("got servings %v scaled by %v, want 4 scaled by 2", preview.Servings, preview.Scale)
+	}
+
+	want := []models.RecipeIngredient{
+		{Line: "2 cups flour", Name: "flour", Quantity: 4, Unit: "cup"},
+		{Line: "2 tablespoons sugar", Name: "sugar", Quantity: 4, Unit: "tbl"},
+		{Line: "2 eggs", Name: "egg", Quantity: 4},
+		{Line: "1 1/2 cups buttermilk", Name: "buttermilk", Quantity: 3, Unit: "cup"},
+		{Line: "1/4 cup butter", Name: "butter", Quantity: 0.5, Unit: "cup"},
+	}
+	if !reflect.DeepEqual(preview.Ingredients, want) {
+		t.Errorf("got ingredients %+v, want %+v", preview.Ingredients, want)
+	}
+
+	// The title, headings and method are not ingredients
+	if len(preview.Skipped) != 6 {
+		t.Errorf("got skipped %q, want the title, servings, headings and method", preview.Skipped)
+	}
+}
+
+func TestPreviewRecipePDFWithoutText(t *testing.T) {
+	if _, err := PreviewRecipePDF("scan.pdf", readTestPDF(t, "scan.pdf"), 0); err == nil {
+		t.Error("a PDF without text was previewed")
+	}
+
+	if _, err := PreviewRecipePDF("recipe.txt", []byte("2 cups flour"), 0); !errors.Is(err, extract.ErrNotPDF) {
+		t.Errorf("text file: got %v, want ErrNotPDF", err)
+	}
+}
+
+func TestPreviewRecipeLines(t *testing.T) {
+	lines := []extract.OCRLine{
+		{Text: "Omelette", Confidence: 95},
+		{Text: "- 12 eggs", Confidence: 40},
+		{Text: "- 2 eggs", Confidence: 90},
+		{Text: "1 cup milk", Confidence: 90},
+		{Text: "1 cup milk", Confidence: 50},
+	}
+
+	preview, err := previewRecipeLines("omelette.jpg", lines, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the lines Tesseract was unsure of are flagged, not the lines that
+	// read the same or end the same
+	var got []string
+	for _, ingredient := range preview.Ingredients {
+		if ingredient.Review {
+			got = append(got, ingredient.Line)
+		} else {
+			got = append(got, "")
+		}
+	}
+	want := []string{"12 eggs", "", "", "1 cup milk"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got review lines %q, want %q from %+v", got, want, preview.Ingredients)
+	}
+
+	if _, err := previewRecipeLines("blank.jpg", nil, 0); err == nil {
+		t.Error("an image without text was previewed")
+	}
+}
@@ -0,0 +1,145 @@
+package providers
+
+import (
+	"api/merge"
+	"api/models"
+	"api/prices"
+	"api/proxy"
+	"fmt"
+	"strings"
+)
+
+// SaveRecipe adds a recipe to the household's library, e.g. one that was
+// previewed from a photo and corrected
+func SaveRecipe(store proxy.Store, recipe models.SavedRecipe) (*models.SavedRecipe, error) {
+	if _, err := GetOrCreateHousehold(store, recipe.HouseholdId); err != nil {
+		return nil, fmt.Errorf("Could not get or create household %s: %w", recipe.HouseholdId, err)
+	}
+
+	return store.CreateSavedRecipe(recipe)
+}
+
+// ListSavedRecipes returns the household's recipes by title. When query is
+// given only recipes with every one of its words in their title or in an
+// ingredient's name are returned, so "chicken rice" finds a curry that uses
+// both.
+func ListSavedRecipes(store proxy.Store, householdId string, query string) ([]models.SavedRecipe, error) {
+	recipes, err := store.ListSavedRecipes(householdId)
+	if err != nil {
+		return nil, err
+	}
+
+	words := strings.Fields(prices.NormalizeName(query))
+	if len(words) == 0 {
+		return recipes, nil
+	}
+
+	found := make([]models.SavedRecipe, 0)
+	for _, recipe := range recipes {
+		if savedRecipeMatches(recipe, words) {
+			found = append(found, recipe)
+		}
+	}
+
+	return found, nil
+}
+
+func savedRecipeMatches(recipe models.SavedRecipe, words []string) bool {
+	text := strings.ToLower(recipe.Title)
+	for _, ingredient := range recipe.Ingredients {
+		text += " " + strings.ToLower(ingredient.Name)
+	}
+
+	for _, word := range words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetSavedRecipe returns the recipe if it is in the household's library
+func GetSavedRecipe(store proxy.Store, householdId string, recipeId string) (*models.SavedRecipe, error) {
+	recipe, err := store.GetSavedRecipe(recipeId)
+	if err != nil {
+		return nil, err
+	}
+	if recipe.HouseholdId != householdId {
+		return nil, fmt.Errorf("saved recipe %w", proxy.ErrNotFound)
+	}
+
+	return recipe, nil
+}
+
+// UpdateSavedRecipe corrects a saved recipe. Its ingredients are replaced as
+// a whole, so lines the parser got wrong can be fixed, split or removed.
+func UpdateSavedRecipe(store proxy.Store, householdId string, recipeId string, patch models.SavedRecipePatch) (*models.SavedRecipe, error) {
+	if _, err := GetSavedRecipe(store, householdId, recipeId); err != nil {
+		return nil, err
+	}
+
+	return store.UpdateSavedRecipe(recipeId, patch)
+}
+
+func DeleteSavedRecipe(store proxy.Store, householdId string, recipeId string) error {
+	if _, err := GetSavedRecipe(store, householdId, recipeId); err != nil {
+		return err
+	}
+
+	return store.DeleteSavedRecipe(recipeId)
+}
+
+// AddSavedRecipe merges a saved recipe's ingredients into a list, scaled to
+// feed the servings asked for when the recipe says how many it feeds
+func AddSavedRecipe(store proxy.Store, householdId string, recipeId string, request models.AddSavedRecipeRequest) (*models.AddIngredientsResponse, error) {
+	recipe, err := GetSavedRecipe(store, householdId, recipeId)
+	if err != nil {
+		return nil, err
+	}
+
+	ingredients, _ := savedRecipeIngredients(*recipe, request.Servings)
+	created, updated, err := MergeIngredients(store, householdId, request.ListId, ingredients)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AddIngredientsResponse{
+		Created: append(make([]models.GroceryItem, 0), created...),
+		Updated: append(make([]models.GroceryItem, 0), updated...),
+	}, nil
+}
+
+// savedRecipeIngredients returns the recipe's ingredients scaled to feed
+// servings, and the scale, which is 1 when either is unknown
+func savedRecipeIngredients(recipe models.SavedRecipe, servings float64) ([]merge.Ingredient, float64) {
+	scale := 1.0
+	if servings > 0 && recipe.Servings > 0 {
+		scale = servings / recipe.Servings
+	}
+
+	source := recipe.SourceUrl
+	if source == "" {
+		source = recipe.Title
+	}
+
+	return mergeRecipeIngredients(recipe.Ingredients, source, scale), scale
+}
+
+// savedRecipesByUrl indexes the household's library by the url each recipe
+// was imported from
+func savedRecipesByUrl(store proxy.Store, householdId string) (map[string]*models.SavedRecipe, error) {
+	recipes, err := store.ListSavedRecipes(householdId)
+	if err != nil {
+		return nil, err
+	}
+
+	library := make(map[string]*models.SavedRecipe)
+	for i := range recipes {
+		if recipes[i].SourceUrl != "" {
+			library[recipes[i].SourceUrl] = &recipes[i]
+		}
+	}
+
+	return library, nil
+}
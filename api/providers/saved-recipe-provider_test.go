@@ -0,0 +1,202 @@
+package providers
+
+import (
+	"api/models"
+	"api/proxy"
+	"api/proxy/memory"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newTestSavedRecipe(t *testing.T, store proxy.Store, recipe models.SavedRecipe) *models.SavedRecipe {
+	t.Helper()
+
+	saved, err := SaveRecipe(store, recipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return saved
+}
+
+func savedRecipeTitles(recipes []models.SavedRecipe) []string {
+	titles := make([]string, len(recipes))
+	for i, recipe := range recipes {
+		titles[i] = recipe.Title
+	}
+	return titles
+}
+
+func TestListSavedRecipes(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+	neighbours := newTestHousehold(t, store)
+
+	newTestSavedRecipe(t, store, models.SavedRecipe{HouseholdId: household.Id, Title: "Green curry", Ingredients: []models.RecipeIngredient{
+		{Line: "500 g chicken thighs", Name: "chicken thigh", Quantity: 500, Unit: "g"},
+		{Line: "2 cups jasmine rice", Name: "jasmine rice", Quantity: 2, Unit: "cup"},
+	}})
+	newTestSavedRecipe(t, store, models.SavedRecipe{HouseholdId: household.Id, Title: "Fried rice", Ingredients: []models.RecipeIngredient{
+		{Line: "3 cups rice", Name: "rice", Quantity: 3, Unit: "cup"},
+		{Line: "2 eggs", Name: "egg", Quantity: 2},
+	}})
+	newTestSavedRecipe(t, store, models.SavedRecipe{HouseholdId: household.Id, Title: "Roast chicken", Ingredients: []models.RecipeIngredient{
+		{Line: "1 chicken", Name: "chicken", Quantity: 1},
+	}})
+	newTestSavedRecipe(t, store, models.SavedRecipe{HouseholdId: neighbours.Id, Title: "Chicken and rice", Ingredients: []models.RecipeIngredient{
+		{Line: "1 chicken", Name: "chicken", Quantity: 1},
+	}})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"Fried rice", "Green curry", "Roast chicken"}},
+		// the curry's title has neither word, its ingredients have both
+		{"chicken rice", []string{"Green curry"}},
+		{"Rice", []string{"Fried rice", "Green curry"}},
+		{"🍗 Chickens", []string{"Green curry", "Roast chicken"}},
+		{"fried egg", []string{"Fried rice"}},
+		{"tofu", []string{}},
+	}
+
+	for _, tt := range tests {
+		recipes, err := ListSavedRecipes(store, household.Id, tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if titles := savedRecipeTitles(recipes); !reflect.DeepEqual(titles, tt.want) {
+			t.Errorf("ListSavedRecipes(%q) = %q, want %q", tt.query, titles, tt.want)
+		}
+	}
+}
+
+func TestAddSavedRecipe(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+
+	pancakes := newTestSavedRecipe(t, store, models.SavedRecipe{HouseholdId: household.Id, Title: "Pancakes", Servings: 4, Ingredients: []models.RecipeIngredient{
+		{Line: "2 cups flour", Name: "flour", Quantity: 2, Unit: "cup"},
+		{Line: "3 eggs", Name: "egg", Quantity: 3},
+	}})
+	toast := newTestSavedRecipe(t, store, models.SavedRecipe{HouseholdId: household.Id, Title: "Toast", Ingredients: []models.RecipeIngredient{
+		{Line: "2 slices bread", Name: "bread", Quantity: 2},
+	}})
+
+	steps := []struct {
+		recipe   *models.SavedRecipe
+		servings float64
+		want     map[string]float64
+	}{
+		// 8 servings of a recipe for 4 doubles it
+		{pancakes, 8, map[string]float64{"flour": 4, "egg": 6}},
+		// no servings adds it as saved
+		{pancakes, 0, map[string]float64{"flour": 6, "egg": 9}},
+		{pancakes, 2, map[string]float64{"flour": 7, "egg": 10.5}},
+		// a recipe that doesn't say how many it feeds isn't scaled
+		{toast, 8, map[string]float64{"flour": 7, "egg": 10.5, "bread": 2}},
+	}
+
+	for i, step := range steps {
+		if _, err := AddSavedRecipe(store, household.Id, step.recipe.Id, models.AddSavedRecipeRequest{Servings: step.servings}); err != nil {
+			t.Fatal(err)
+		}
+
+		got := make(map[string]float64)
+		for name, item := range itemsByName(t, store, household.Id) {
+			got[name] = item.Quantity
+		}
+		if !reflect.DeepEqual(got, step.want) {
+			t.Errorf("step %d, %s for %v: got %v, want %v", i, step.recipe.Title, step.servings, got, step.want)
+		}
+	}
+}
+
+// TestImportEditedSavedRecipe corrects an imported recipe, and imports the
+// same url again with the correction instead of the page
+func TestImportEditedSavedRecipe(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+	soup := newRecipeServer(t, 0, "Soup", "2 onions", "1 cup chicken broth")
+
+	if _, err := ImportRecipes(store, household.Id, "", []string{soup.URL}, []string{""}, 0); err != nil {
+		t.Fatal(err)
+	}
+	library, err := savedRecipesByUrl(store, household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := library[soup.URL]
+	if saved == nil {
+		t.Fatal("the imported recipe was not saved")
+	}
+
+	// the parser read leeks as onions
+	ingredients := []models.RecipeIngredient{
+		{Line: "2 leeks", Name: "leek", Quantity: 2},
+		{Line: "1 cup chicken broth", Name: "chicken broth", Quantity: 1, Unit: "cup"},
+	}
+	if _, err := UpdateSavedRecipe(store, household.Id, saved.Id, models.SavedRecipePatch{Ingredients: &ingredients}); err != nil {
+		t.Fatal(err)
+	}
+
+	library, err = savedRecipesByUrl(store, household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := library[soup.URL].Ingredients; !reflect.DeepEqual(got, ingredients) {
+		t.Errorf("saved ingredients: got %+v, want the edited ones", got)
+	}
+
+	imported, err := ImportRecipes(store, household.Id, "", []string{soup.URL}, []string{""}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if soup.requests.Load() != 1 {
+		t.Errorf("the page was downloaded %d times, want once", soup.requests.Load())
+	}
+	if names := itemNames(imported.Created); !reflect.DeepEqual(names, []string{"leek"}) {
+		t.Errorf("created %v, want the leeks", names)
+	}
+
+	items := itemsByName(t, store, household.Id)
+	if items["onion"].Quantity != 2 || items["leek"].Quantity != 2 || items["chicken broth"].Quantity != 2 {
+		t.Errorf("got %+v, want the first import's onions and the edited recipe's leeks", items)
+	}
+}
+
+func TestSavedRecipeOfAnotherHousehold(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+	neighbours := newTestHousehold(t, store)
+
+	theirs := newTestSavedRecipe(t, store, models.SavedRecipe{HouseholdId: neighbours.Id, Title: "Toast", Ingredients: []models.RecipeIngredient{
+		{Line: "2 slices bread", Name: "bread", Quantity: 2},
+	}})
+
+	if _, err := GetSavedRecipe(store, household.Id, theirs.Id); !errors.Is(err, proxy.ErrNotFound) {
+		t.Errorf("GetSavedRecipe: got %v, want ErrNotFound", err)
+	}
+	title := "Our toast"
+	if _, err := UpdateSavedRecipe(store, household.Id, theirs.Id, models.SavedRecipePatch{Title: &title}); !errors.Is(err, proxy.ErrNotFound) {
+		t.Errorf("UpdateSavedRecipe: got %v, want ErrNotFound", err)
+	}
+	if _, err := AddSavedRecipe(store, household.Id, theirs.Id, models.AddSavedRecipeRequest{}); !errors.Is(err, proxy.ErrNotFound) {
+		t.Errorf("AddSavedRecipe: got %v, want ErrNotFound", err)
+	}
+	if err := DeleteSavedRecipe(store, household.Id, theirs.Id); !errors.Is(err, proxy.ErrNotFound) {
+		t.Errorf("DeleteSavedRecipe: got %v, want ErrNotFound", err)
+	}
+
+	recipe, err := GetSavedRecipe(store, neighbours.Id, theirs.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recipe.Title != "Toast" {
+		t.Errorf("their recipe was changed to %q", recipe.Title)
+	}
+	if items := itemsByName(t, store, household.Id); len(items) != 0 {
+		t.Errorf("their recipe was added to our list: %+v", items)
+	}
+}
@@ -0,0 +1,248 @@
+package providers
+
+import (
+	"api/models"
+	"api/parsing"
+	"api/prices"
+	"api/proxy"
+	"sort"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+)
+
+// storePreferences is everything AssignStores needs about a household, loaded
+// once per request
+type storePreferences struct {
+	preferredStores   []models.StorePreference
+	history           map[string]models.StorePreference
+	rules             map[string]models.StorePreference
+	categoryOverrides map[string]string
+	catalog           *prices.Catalog
+}
+
+// AssignStores decides which store each item is bought at, keyed by item id.
+// In order of precedence it uses the item's StoreOverride, the store the
+// household has put the item at most often, the household's rule for the
+// item's category, the cheapest preferred store that has a price for the item,
+// and otherwise the first preferred store. History, rules and prices only
+// count when they point at a preferred store, unless there are none.
+func AssignStores(store proxy.Store, householdId string, preferredStores []models.StorePreference, groceryItems []models.GroceryItem) (map[string]models.StorePreference, error) {
+	preferences, err := loadStorePreferences(store, householdId, preferredStores)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := make(map[string]models.StorePreference, len(groceryItems))
+	for _, groceryItem := range groceryItems {
+		assignments[groceryItem.Id] = preferences.assign(groceryItem)
+	}
+
+	return assignments, nil
+}
+
+func loadStorePreferences(store proxy.Store, householdId string, preferredStores []models.StorePreference) (*storePreferences, error) {
+	preferences := &storePreferences{
+		preferredStores: preferredStores,
+		history:         make(map[string]models.StorePreference),
+		rules:           make(map[string]models.StorePreference),
+	}
+
+	// Choices come most used first, so the first usable one per item wins
+	choices, err := store.ListStoreHistory(householdId)
+	if err != nil {
+		return nil, err
+	}
+	for _, choice := range choices {
+		if _, ok := preferences.history[choice.ItemName]; !ok && preferences.isPreferred(choice.Store) {
+			preferences.history[choice.ItemName] = choice.Store
+		}
+	}
+
+	rules, err := store.ListStoreRules(householdId)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if preferences.isPreferred(rule.Store) {
+			preferences.rules[strings.ToLower(rule.Category)] = rule.Store
+		}
+	}
+
+	preferences.categoryOverrides, err = categoryOverrides(store, householdId)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences.catalog, err = loadCatalog(store)
+	if err != nil {
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+func (preferences *storePreferences) assign(groceryItem models.GroceryItem) models.StorePreference {
+	if groceryItem.Kind == models.TaskKind {
+		return models.Unknown
+	}
+
+	if groceryItem.StoreOverride != "" {
+		return groceryItem.StoreOverride
+	}
+
+	if store, ok := preferences.history[storeHistoryKey(groceryItem.Name)]; ok {
+		return store
+	}
+
+	category := groceryItem.Category
+	if category == "" {
+		category = lookupCategory(preferences.categoryOverrides, groceryItem.Name)
+	}
+	if store, ok := preferences.rules[strings.ToLower(category)]; ok {
+		return store
+	}
+
+	return preferences.cheapestStoreForItemOrStorePreference(groceryItem.Name)
+}
+
+func (preferences *storePreferences) cheapestStoreForItemOrStorePreference(itemName string) models.StorePreference {
+	if store, ok := preferences.catalog.Cheapest(itemName, preferences.preferredStores); ok {
+		return store
+	}
+
+	if len(preferences.preferredStores) > 0 {
+		return preferences.preferredStores[0]
+	}
+
+	return models.Unknown
+}
+
+func (preferences *storePreferences) isPreferred(store models.StorePreference) bool {
+	if len(preferences.preferredStores) == 0 {
+		return true
+	}
+
+	for _, preferred := range preferences.preferredStores {
+		if preferred == store {
+			return true
+		}
+	}
+
+	return false
+}
+
+// knownStores are the stores the household prefers, has rules for or that
+// have prices, the names a layout header can be taken to be a store by
+func knownStores(store proxy.Store, householdId string, preferredStores []models.StorePreference) (map[models.StorePreference]bool, error) {
+	stores := make(map[models.StorePreference]bool)
+	for _, preferred := range preferredStores {
+		stores[preferred] = true
+	}
+
+	storePrices, err := store.ListStorePrices()
+	if err != nil {
+		return nil, err
+	}
+	for _, storePrice := range storePrices {
+		stores[storePrice.StoreName] = true
+	}
+
+	rules, err := store.ListStoreRules(householdId)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		stores[rule.Store] = true
+	}
+
+	delete(stores, "")
+	delete(stores, models.Unknown)
+	return stores, nil
+}
+
+// storeHistoryKey makes "🧅 Onions" and "onion" share a history
+func storeHistoryKey(itemName string) string {
+	return inflection.Singular(parsing.ParseItemName(itemName))
+}
+
+// RecordStoreChoice remembers that the household put the item at the store, so
+// AssignStores picks the same store the next time the item is added
+func RecordStoreChoice(store proxy.Store, householdId string, itemName string, storePreference models.StorePreference) error {
+	if storePreference == "" || storePreference == models.Unknown {
+		return nil
+	}
+
+	return store.RecordStoreChoice(householdId, storeHistoryKey(itemName), storePreference)
+}
+
+// LayoutByStore groups the items under a Text header per store. Preferred
+// stores come first in the order given, then other stores by name, and
+// unknown last. Items keep their order within a store.
+func LayoutByStore(groceryItems []models.GroceryItem, assignments map[string]models.StorePreference, preferredStores []models.StorePreference) []models.LayoutBlock {
+	rank := make(map[models.StorePreference]int, len(preferredStores))
+	for i, preferred := range preferredStores {
+		if _, ok := rank[preferred]; !ok {
+			rank[preferred] = i
+		}
+	}
+
+	var stores []models.StorePreference
+	blocksByStore := make(map[models.StorePreference][]models.LayoutBlock)
+	for _, groceryItem := range groceryItems {
+		store, ok := assignments[groceryItem.Id]
+		if !ok {
+			store = models.Unknown
+		}
+		if _, ok := blocksByStore[store]; !ok {
+			stores = append(stores, store)
+		}
+		blocksByStore[store] = append(blocksByStore[store], models.LayoutBlock{Value: groceryItem.Id, Type: models.GroceryItemId})
+	}
+
+	sort.SliceStable(stores, func(i, j int) bool {
+		if (stores[i] == models.Unknown) != (stores[j] == models.Unknown) {
+			return stores[j] == models.Unknown
+		}
+		iRank, iIsPreferred := rank[stores[i]]
+		jRank, jIsPreferred := rank[stores[j]]
+		if iIsPreferred != jIsPreferred {
+			return iIsPreferred
+		}
+		if iIsPreferred {
+			return iRank < jRank
+		}
+		return stores[i] < stores[j]
+	})
+
+	var layout []models.LayoutBlock
+	for _, store := range stores {
+		layout = append(layout, models.LayoutBlock{Value: string(store), Type: models.Text})
+		layout = append(layout, blocksByStore[store]...)
+	}
+
+	return layout
+}
+
+func GetStoreRules(store proxy.Store, householdId string) ([]models.StoreRule, error) {
+	if _, err := store.GetHousehold(householdId); err != nil {
+		return nil, err
+	}
+
+	return store.ListStoreRules(householdId)
+}
+
+func SaveStoreRule(store proxy.Store, rule models.StoreRule) (*models.StoreRule, error) {
+	rule.Category = strings.TrimSpace(rule.Category)
+	rule.Store = models.StorePreference(strings.TrimSpace(string(rule.Store)))
+
+	if err := store.SaveStoreRule(rule); err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func DeleteStoreRule(store proxy.Store, householdId string, category string) error {
+	return store.DeleteStoreRule(householdId, category)
+}
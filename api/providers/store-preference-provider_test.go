@@ -0,0 +1,130 @@
+package providers
+
+import (
+	"api/models"
+	"api/proxy/memory"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAssignStores(t *testing.T) {
+	store := memory.NewStore()
+	household := newTestHousehold(t, store)
+	preferred := []models.StorePreference{"Aldi", "Coles", "Woolworths"}
+
+	// Aldi is cheapest for every dairy item, Coles for the bread
+	now := time.Now()
+	err := store.SaveStorePrices([]models.StoreData{
+		{ItemName: "milk", StoreName: "Aldi", Price: 1.49, LastUpdated: now},
+		{ItemName: "cheese", StoreName: "Aldi", Price: 5.00, LastUpdated: now},
+		{ItemName: "yogurt", StoreName: "Aldi", Price: 2.00, LastUpdated: now},
+		{ItemName: "yogurt", StoreName: "Woolworths", Price: 2.50, LastUpdated: now},
+		{ItemName: "bread", StoreName: "Aldi", Price: 3.50, LastUpdated: now},
+		{ItemName: "bread", StoreName: "Coles", Price: 3.00, LastUpdated: now},
+		{ItemName: "bread", StoreName: "IGA", Price: 2.00, LastUpdated: now},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SaveStoreRule(store, models.StoreRule{HouseholdId: household.Id, Category: "dairy", Store: "Woolworths"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SaveStoreRule(store, models.StoreRule{HouseholdId: household.Id, Category: "Fruit", Store: "IGA"}); err != nil {
+		t.Fatal(err)
+	}
+	for itemName, storeName := range map[string]models.StorePreference{"Milk": "Coles", "🧀 Cheeses": "Coles", "Apples": "IGA"} {
+		if err := RecordStoreChoice(store, household.Id, itemName, storeName); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	groceryItems := []models.GroceryItem{
+		{Id: "override", Name: "Milk", Category: "Dairy", StoreOverride: "IGA"},
+		{Id: "history", Name: "cheese", Category: "Dairy"},
+		{Id: "rule", Name: "Yogurt"},
+		{Id: "cheapest", Name: "Bread", Category: "Bakery"},
+		{Id: "first", Name: "Apples", Category: "Fruit"},
+		{Id: "new", Name: "Something new"},
+		{Id: "task", Name: "Call the plumber", Kind: models.TaskKind},
+	}
+
+	// Coles has the history for milk, Woolworths the dairy rule and Aldi
+	// the cheapest milk, but the override wins. History, rules and prices
+	// at IGA don't count since it isn't preferred.
+	want := map[string]models.StorePreference{
+		"override": "IGA",
+		"history":  "Coles",
+		"rule":     "Woolworths",
+		"cheapest": "Coles",
+		"first":    "Aldi",
+		"new":      "Aldi",
+		"task":     models.Unknown,
+	}
+	got, err := AssignStores(store, household.Id, preferred, groceryItems)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Without preferred stores everything counts, and items nothing is known
+	// about have no store
+	want = map[string]models.StorePreference{
+		"override": "IGA",
+		"history":  "Coles",
+		"rule":     "Woolworths",
+		"cheapest": "IGA",
+		"first":    "IGA",
+		"new":      models.Unknown,
+		"task":     models.Unknown,
+	}
+	got, err = AssignStores(store, household.Id, nil, groceryItems)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("without preferred stores: got %v, want %v", got, want)
+	}
+}
+
+func TestLayoutByStore(t *testing.T) {
+	groceryItems := []models.GroceryItem{
+		{Id: "milk"}, {Id: "bread"}, {Id: "cheese"}, {Id: "apples"}, {Id: "soap"}, {Id: "eggs"}, {Id: "tea"},
+	}
+	assignments := map[string]models.StorePreference{
+		"milk":   "IGA",
+		"bread":  "Coles",
+		"cheese": "Bakers Delight",
+		"apples": models.Unknown,
+		"soap":   "Aldi",
+		"eggs":   "Coles",
+		// tea has no assignment
+	}
+
+	// Preferred stores in the order given, then the others by name, and the
+	// items without a store last
+	want := []models.LayoutBlock{
+		{Value: "Aldi", Type: models.Text},
+		{Value: "soap", Type: models.GroceryItemId},
+		{Value: "Coles", Type: models.Text},
+		{Value: "bread", Type: models.GroceryItemId},
+		{Value: "eggs", Type: models.GroceryItemId},
+		{Value: "Bakers Delight", Type: models.Text},
+		{Value: "cheese", Type: models.GroceryItemId},
+		{Value: "IGA", Type: models.Text},
+		{Value: "milk", Type: models.GroceryItemId},
+		{Value: string(models.Unknown), Type: models.Text},
+		{Value: "apples", Type: models.GroceryItemId},
+		{Value: "tea", Type: models.GroceryItemId},
+	}
+	got := LayoutByStore(groceryItems, assignments, []models.StorePreference{"Aldi", "Woolworths", "Coles", "Aldi"})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if got := LayoutByStore(nil, nil, []models.StorePreference{"Aldi"}); len(got) != 0 {
+		t.Errorf("no items: got %+v, want an empty layout", got)
+	}
+}
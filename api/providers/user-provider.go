@@ -2,35 +2,30 @@ package providers
 
 import (
 	"api/models"
-	db "api/proxy/sqlite"
+	"api/proxy"
 )
 
 var usersTableName = "Users"
 
-func CreateUser() *models.User {
-	database, _ := db.NewDB()
-	defer database.Close()
+func CreateUser(store proxy.Store) (*models.User, error) {
+	user, err := store.CreateUser("")
+	if err != nil {
+		return nil, err
+	}
 
-	user, _ := database.CreateUser("")
 	user.HouseholdIds = []string{}
-	return user
+	return user, nil
 }
 
-func UpdateUser(user models.User) error {
-	database, _ := db.NewDB()
-	defer database.Close()
-
-	return database.UpdateUser(user.Id, user.Name)
+func UpdateUser(store proxy.Store, user models.User) error {
+	return store.UpdateUser(user.Id, user.Name)
 }
 
-func GetOrCreateUser(id string) (*models.User, error) {
-	database, _ := db.NewDB()
-	defer database.Close()
-
-	user, err := database.GetUser(id)
+func GetOrCreateUser(store proxy.Store, id string) (*models.User, error) {
+	user, err := store.GetUser(id)
 
 	if user != nil && err == nil {
-		households, _ := database.GetUserHouseholds(user.Id)
+		households, _ := store.GetUserHouseholds(user.Id)
 		var householdIds []string
 		for _, household := range households {
 			householdIds = append(householdIds, household.Id)
@@ -39,5 +34,5 @@ func GetOrCreateUser(id string) (*models.User, error) {
 		return user, nil
 	}
 
-	return CreateUser(), nil
+	return CreateUser(store)
 }
@@ -0,0 +1,1203 @@
+package memory
+
+import (
+	"api/models"
+	"api/proxy"
+	"fmt"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Store keeps everything in maps guarded by a single mutex. It mirrors the
+// behaviour of the sqlite proxy, including cascading deletes, so it can stand
+// in for it in tests.
+type Store struct {
+	mu sync.RWMutex
+	// txMu is held by a Transaction until it ends
+	txMu sync.Mutex
+
+	data
+}
+
+// data is everything the store holds, which a Transaction copies to put back
+// if it fails
+type data struct {
+	households     map[string]models.Household
+	users          map[string]models.User
+	householdUsers map[string]map[string]struct{}
+	groceryLists   map[string]models.GroceryList
+	groceryItems   map[string]models.GroceryItem
+	schedules      map[string][]string
+	layouts        map[string][]models.LayoutBlock
+	// categoryOverrides is keyed by household id, then item name
+	categoryOverrides map[string]map[string]string
+	// storeRules is keyed by household id, then category
+	storeRules map[string]map[string]models.StorePreference
+	// storeHistory is keyed by household id, then item name, then store
+	storeHistory map[string]map[string]map[models.StorePreference]int
+	// storePrices is keyed by item name, then store
+	storePrices map[string]map[models.StorePreference]models.StoreData
+	// priceChecks is keyed by item name, then store
+	priceChecks      map[string]map[models.StorePreference]models.PriceCheck
+	recipeImportJobs map[string]models.RecipeImportJob
+	savedRecipes     map[string]models.SavedRecipe
+}
+
+var _ proxy.Store = (*Store)(nil)
+
+func NewStore() *Store {
+	return &Store{data: data{
+		households:        make(map[string]models.Household),
+		users:             make(map[string]models.User),
+		householdUsers:    make(map[string]map[string]struct{}),
+		groceryLists:      make(map[string]models.GroceryList),
+		groceryItems:      make(map[string]models.GroceryItem),
+		schedules:         make(map[string][]string),
+		layouts:           make(map[string][]models.LayoutBlock),
+		categoryOverrides: make(map[string]map[string]string),
+		storeRules:        make(map[string]map[string]models.StorePreference),
+		storeHistory:      make(map[string]map[string]map[models.StorePreference]int),
+		storePrices:       make(map[string]map[models.StorePreference]models.StoreData),
+		priceChecks:       make(map[string]map[models.StorePreference]models.PriceCheck),
+		recipeImportJobs:  make(map[string]models.RecipeImportJob),
+		savedRecipes:      make(map[string]models.SavedRecipe),
+	}}
+}
+
+// clone copies the maps, nested ones included, so changing the copy leaves d
+// as it was. The values are shared, as the store replaces them rather than
+// changing them in place.
+func (d data) clone() data {
+	storeHistory := make(map[string]map[string]map[models.StorePreference]int, len(d.storeHistory))
+	for householdId, history := range d.storeHistory {
+		storeHistory[householdId] = cloneNested(history)
+	}
+
+	return data{
+		households:        maps.Clone(d.households),
+		users:             maps.Clone(d.users),
+		householdUsers:    cloneNested(d.householdUsers),
+		groceryLists:      maps.Clone(d.groceryLists),
+		groceryItems:      maps.Clone(d.groceryItems),
+		schedules:         maps.Clone(d.schedules),
+		layouts:           maps.Clone(d.layouts),
+		categoryOverrides: cloneNested(d.categoryOverrides),
+		storeRules:        cloneNested(d.storeRules),
+		storeHistory:      storeHistory,
+		storePrices:       cloneNested(d.storePrices),
+		priceChecks:       cloneNested(d.priceChecks),
+		recipeImportJobs:  maps.Clone(d.recipeImportJobs),
+		savedRecipes:      maps.Clone(d.savedRecipes),
+	}
+}
+
+func cloneNested[K1, K2 comparable, V any](m map[K1]map[K2]V) map[K1]map[K2]V {
+	cloned := make(map[K1]map[K2]V, len(m))
+	for key, inner := range m {
+		cloned[key] = maps.Clone(inner)
+	}
+
+	return cloned
+}
+
+// Transaction runs fn with the store, holding txMu so that transactions run
+// one at a time, and puts back what the store held if fn fails. Calls made
+// outside a transaction do not wait for it and see its changes straight away.
+func (s *Store) Transaction(fn func(tx proxy.Store) error) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	s.mu.RLock()
+	saved := s.data.clone()
+	s.mu.RUnlock()
+
+	if err := fn(txStore{s}); err != nil {
+		s.mu.Lock()
+		s.data = saved
+		s.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// txStore is the store as given to the function of a Transaction
+type txStore struct {
+	*Store
+}
+
+func (tx txStore) Transaction(fn func(tx proxy.Store) error) error {
+	return fn(tx)
+}
+
+func newId() string {
+	uuidv7, _ := uuid.NewV7()
+	return uuidv7.String()
+}
+
+// Household Methods
+func (s *Store) CreateHousehold(name string) (*models.Household, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	household := models.Household{Id: newId(), Name: name}
+	s.createHousehold(household)
+
+	return &household, nil
+}
+
+func (s *Store) CreateUserHousehold(id string) (*models.Household, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.households[id]; ok {
+		return nil, fmt.Errorf("failed to create household: household %s already exists", id)
+	}
+
+	household := models.Household{Id: id, Name: id}
+	s.createHousehold(household)
+
+	return &household, nil
+}
+
+// createHousehold adds the household together with its default list. Callers
+// hold the lock.
+func (s *Store) createHousehold(household models.Household) {
+	s.households[household.Id] = household
+	s.groceryLists[household.Id] = models.GroceryList{
+		Id:          household.Id,
+		HouseholdId: household.Id,
+		Name:        models.DefaultGroceryListName,
+	}
+}
+
+func (s *Store) GetHousehold(id string) (*models.Household, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	household, ok := s.households[id]
+	if !ok {
+		return nil, fmt.Errorf("household %w", proxy.ErrNotFound)
+	}
+
+	return &household, nil
+}
+
+func (s *Store) UpdateHousehold(id, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	household, ok := s.households[id]
+	if !ok {
+		return fmt.Errorf("household %w", proxy.ErrNotFound)
+	}
+
+	household.Name = name
+	s.households[id] = household
+
+	return nil
+}
+
+// SetHouseholdMeasurementSystem saves the system recipe quantities are
+// converted to, the empty system keeping them as written
+func (s *Store) SetHouseholdMeasurementSystem(id string, system models.MeasurementSystem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	household, ok := s.households[id]
+	if !ok {
+		return fmt.Errorf("household %w", proxy.ErrNotFound)
+	}
+
+	household.MeasurementSystem = system
+	s.households[id] = household
+
+	return nil
+}
+
+func (s *Store) DeleteHousehold(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.households[id]; !ok {
+		return fmt.Errorf("household %w", proxy.ErrNotFound)
+	}
+
+	delete(s.households, id)
+	delete(s.householdUsers, id)
+	delete(s.categoryOverrides, id)
+	delete(s.storeRules, id)
+	delete(s.storeHistory, id)
+	for listId, list := range s.groceryLists {
+		if list.HouseholdId == id {
+			s.deleteGroceryList(listId)
+		}
+	}
+	for itemId, item := range s.groceryItems {
+		if item.HouseholdId == id {
+			s.deleteGroceryItem(itemId)
+		}
+	}
+	for recipeId, recipe := range s.savedRecipes {
+		if recipe.HouseholdId == id {
+			delete(s.savedRecipes, recipeId)
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) ListHouseholds() ([]models.Household, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var households []models.Household
+	for _, household := range s.households {
+		households = append(households, household)
+	}
+	sortHouseholds(households)
+
+	return households, nil
+}
+
+func (s *Store) CreateUser(name string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := models.User{Id: newId(), Name: name}
+	s.users[user.Id] = user
+
+	return &user, nil
+}
+
+func (s *Store) GetUser(id string) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return nil, fmt.Errorf("user %w", proxy.ErrNotFound)
+	}
+
+	return &user, nil
+}
+
+func (s *Store) UpdateUser(id string, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return fmt.Errorf("user %w", proxy.ErrNotFound)
+	}
+
+	user.Name = name
+	s.users[id] = user
+
+	return nil
+}
+
+func (s *Store) DeleteUser(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[id]; !ok {
+		return fmt.Errorf("user %w", proxy.ErrNotFound)
+	}
+
+	delete(s.users, id)
+	for _, members := range s.householdUsers {
+		delete(members, id)
+	}
+
+	return nil
+}
+
+// ListUsers returns all users
+func (s *Store) ListUsers() ([]models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var users []models.User
+	for _, user := range s.users {
+		users = append(users, user)
+	}
+	sortUsers(users)
+
+	return users, nil
+}
+
+// Household-User Methods
+func (s *Store) AddUserToHousehold(userId, householdId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userId]; !ok {
+		return fmt.Errorf("user %w", proxy.ErrNotFound)
+	}
+	if _, ok := s.households[householdId]; !ok {
+		return fmt.Errorf("household %w", proxy.ErrNotFound)
+	}
+
+	members, ok := s.householdUsers[householdId]
+	if !ok {
+		members = make(map[string]struct{})
+		s.householdUsers[householdId] = members
+	}
+	if _, ok := members[userId]; ok {
+		return fmt.Errorf("failed to add user to household: user is already a member")
+	}
+	members[userId] = struct{}{}
+
+	return nil
+}
+
+// RemoveUserFromHousehold dissociates a user from a household
+func (s *Store) RemoveUserFromHousehold(userId, householdId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	members := s.householdUsers[householdId]
+	if _, ok := members[userId]; !ok {
+		return fmt.Errorf("user %w in household", proxy.ErrNotFound)
+	}
+	delete(members, userId)
+
+	return nil
+}
+
+// GetHouseholdUsers returns all users in a household
+func (s *Store) GetHouseholdUsers(householdId string) ([]models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.households[householdId]; !ok {
+		return nil, fmt.Errorf("household %w", proxy.ErrNotFound)
+	}
+
+	var users []models.User
+	for userId := range s.householdUsers[householdId] {
+		users = append(users, s.users[userId])
+	}
+	sortUsers(users)
+
+	return users, nil
+}
+
+// GetUserHouseholds returns all households a user belongs to
+func (s *Store) GetUserHouseholds(userId string) ([]models.Household, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.users[userId]; !ok {
+		return nil, fmt.Errorf("user %w", proxy.ErrNotFound)
+	}
+
+	var households []models.Household
+	for householdId, members := range s.householdUsers {
+		if _, ok := members[userId]; ok {
+			households = append(households, s.households[householdId])
+		}
+	}
+	sortHouseholds(households)
+
+	return households, nil
+}
+
+// Grocery List Methods
+
+// CreateGroceryList adds a list to a household, keeping its Id if it already has one
+func (s *Store) CreateGroceryList(list models.GroceryList) (*models.GroceryList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.households[list.HouseholdId]; !ok {
+		return nil, fmt.Errorf("household %w", proxy.ErrNotFound)
+	}
+
+	list.GetOrGenerateID()
+	if _, ok := s.groceryLists[list.Id]; ok {
+		return nil, fmt.Errorf("failed to create grocery list: %s already exists", list.Id)
+	}
+	list.Items = nil
+	list.Layout = nil
+	s.groceryLists[list.Id] = list
+
+	return &list, nil
+}
+
+func (s *Store) GetGroceryList(id string) (*models.GroceryList, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list, ok := s.groceryLists[id]
+	if !ok {
+		return nil, fmt.Errorf("grocery list %w", proxy.ErrNotFound)
+	}
+
+	return &list, nil
+}
+
+// LockGroceryList only checks the list exists: transactions already run one
+// at a time
+func (s *Store) LockGroceryList(id string) error {
+	_, err := s.GetGroceryList(id)
+	return err
+}
+
+// ListGroceryLists returns the household's lists, default list first
+func (s *Store) ListGroceryLists(householdId string) ([]models.GroceryList, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	lists := make([]models.GroceryList, 0)
+	for _, list := range s.groceryLists {
+		if list.HouseholdId == householdId {
+			lists = append(lists, list)
+		}
+	}
+	sort.SliceStable(lists, func(i, j int) bool {
+		iIsDefault, jIsDefault := lists[i].Id == householdId, lists[j].Id == householdId
+		if iIsDefault != jIsDefault {
+			return iIsDefault
+		}
+		return lists[i].Name < lists[j].Name
+	})
+
+	return lists, nil
+}
+
+func (s *Store) RenameGroceryList(id, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, ok := s.groceryLists[id]
+	if !ok {
+		return fmt.Errorf("grocery list %w", proxy.ErrNotFound)
+	}
+
+	list.Name = name
+	s.groceryLists[id] = list
+
+	return nil
+}
+
+// DeleteGroceryList deletes the list along with its items and layout
+func (s *Store) DeleteGroceryList(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.groceryLists[id]; !ok {
+		return fmt.Errorf("grocery list %w", proxy.ErrNotFound)
+	}
+
+	s.deleteGroceryList(id)
+
+	return nil
+}
+
+// deleteGroceryList removes a list, its layout, its items and its recipe
+// import jobs. Callers hold the lock.
+func (s *Store) deleteGroceryList(id string) {
+	delete(s.groceryLists, id)
+	delete(s.layouts, id)
+	for itemId, item := range s.groceryItems {
+		if item.ListId == id {
+			s.deleteGroceryItem(itemId)
+		}
+	}
+	for jobId, job := range s.recipeImportJobs {
+		if job.ListId == id {
+			delete(s.recipeImportJobs, jobId)
+		}
+	}
+}
+
+// Grocery Item Methods
+
+// CreateGroceryItem adds a new grocery item, keeping its Id if it already has
+// one. Items without a ListId go on the household's default list.
+func (s *Store) CreateGroceryItem(item models.GroceryItem) (*models.GroceryItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.households[item.HouseholdId]; !ok {
+		return nil, fmt.Errorf("household %w", proxy.ErrNotFound)
+	}
+
+	item.GetOrGenerateID()
+	if item.ListId == "" {
+		item.ListId = item.HouseholdId
+	}
+	if _, ok := s.groceryLists[item.ListId]; !ok {
+		return nil, fmt.Errorf("grocery list %w", proxy.ErrNotFound)
+	}
+	if _, ok := s.groceryItems[item.Id]; ok {
+		return nil, fmt.Errorf("failed to create grocery item: %s already exists", item.Id)
+	}
+	s.groceryItems[item.Id] = item
+
+	return &item, nil
+}
+
+// GetGroceryItem retrieves a grocery item by Id
+func (s *Store) GetGroceryItem(id string) (*models.GroceryItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	item, ok := s.groceryItems[id]
+	if !ok {
+		return nil, fmt.Errorf("grocery item %w", proxy.ErrNotFound)
+	}
+
+	return &item, nil
+}
+
+func (s *Store) UpdateGroceryItemStatus(id string, checked bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.groceryItems[id]
+	if !ok {
+		return fmt.Errorf("grocery item %w", proxy.ErrNotFound)
+	}
+
+	item.Checked = checked
+	s.groceryItems[id] = item
+
+	return nil
+}
+
+// UpdateGroceryItem writes the fields set in the patch and returns the updated item
+func (s *Store) UpdateGroceryItem(id string, patch models.GroceryItemPatch) (*models.GroceryItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.groceryItems[id]
+	if !ok {
+		return nil, fmt.Errorf("grocery item %w", proxy.ErrNotFound)
+	}
+
+	if patch.Name != nil {
+		item.Name = *patch.Name
+	}
+	if patch.Kind != nil {
+		item.Kind = *patch.Kind
+	}
+	if patch.Category != nil {
+		item.Category = *patch.Category
+	}
+	if patch.StoreOverride != nil {
+		item.StoreOverride = *patch.StoreOverride
+	}
+	if patch.Checked != nil {
+		item.Checked = *patch.Checked
+	}
+	if patch.Quantity != nil {
+		item.Quantity = *patch.Quantity
+	}
+	if patch.Unit != nil {
+		item.Unit = *patch.Unit
+	}
+	if patch.ExtraQuantities != nil {
+		item.ExtraQuantities = append([]models.Quantity(nil), *patch.ExtraQuantities...)
+	}
+	if patch.Sources != nil {
+		item.Sources = append([]string(nil), *patch.Sources...)
+	}
+	if patch.ListId != nil {
+		if _, ok := s.groceryLists[*patch.ListId]; !ok {
+			return nil, fmt.Errorf("grocery list %w", proxy.ErrNotFound)
+		}
+		item.ListId = *patch.ListId
+	}
+	s.groceryItems[id] = item
+
+	return &item, nil
+}
+
+func (s *Store) DeleteGroceryItems(ids []string) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("no Ids provided")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for _, id := range ids {
+		if _, ok := s.groceryItems[id]; ok {
+			s.deleteGroceryItem(id)
+			deleted++
+		}
+	}
+	if deleted == 0 {
+		return fmt.Errorf("grocery items to delete %w", proxy.ErrNotFound)
+	}
+
+	return nil
+}
+
+// deleteGroceryItem removes an item and its schedule. Callers hold the lock.
+func (s *Store) deleteGroceryItem(id string) {
+	delete(s.groceryItems, id)
+	delete(s.schedules, id)
+}
+
+func (s *Store) ListGroceryItemsByHousehold(householdId string) ([]models.GroceryItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.households[householdId]; !ok {
+		return nil, fmt.Errorf("household %w", proxy.ErrNotFound)
+	}
+
+	items := make([]models.GroceryItem, 0)
+	for _, item := range s.groceryItems {
+		if item.HouseholdId == householdId {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+
+	return items, nil
+}
+
+func (s *Store) ListGroceryItemsByList(listId string) ([]models.GroceryItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.groceryLists[listId]; !ok {
+		return nil, fmt.Errorf("grocery list %w", proxy.ErrNotFound)
+	}
+
+	items := make([]models.GroceryItem, 0)
+	for _, item := range s.groceryItems {
+		if item.ListId == listId {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+
+	return items, nil
+}
+
+// Layout Methods
+
+// GetLayout returns the list's saved layout, empty if it has none
+func (s *Store) GetLayout(listId string) ([]models.LayoutBlock, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append(make([]models.LayoutBlock, 0), s.layouts[listId]...), nil
+}
+
+// SaveLayout replaces the list's layout
+func (s *Store) SaveLayout(listId string, layout []models.LayoutBlock) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.groceryLists[listId]; !ok {
+		return fmt.Errorf("grocery list %w", proxy.ErrNotFound)
+	}
+
+	s.layouts[listId] = append([]models.LayoutBlock(nil), layout...)
+
+	return nil
+}
+
+// Category Override Methods
+
+// ListCategoryOverrides returns the household's overrides ordered by item name
+func (s *Store) ListCategoryOverrides(householdId string) ([]models.CategoryOverride, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	overrides := make([]models.CategoryOverride, 0)
+	for itemName, category := range s.categoryOverrides[householdId] {
+		overrides = append(overrides, models.CategoryOverride{HouseholdId: householdId, ItemName: itemName, Category: category})
+	}
+	sort.SliceStable(overrides, func(i, j int) bool {
+		return overrides[i].ItemName < overrides[j].ItemName
+	})
+
+	return overrides, nil
+}
+
+// SaveCategoryOverride creates the override or replaces the category of an
+// existing one for the same item name
+func (s *Store) SaveCategoryOverride(override models.CategoryOverride) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.households[override.HouseholdId]; !ok {
+		return fmt.Errorf("household %w", proxy.ErrNotFound)
+	}
+
+	overrides, ok := s.categoryOverrides[override.HouseholdId]
+	if !ok {
+		overrides = make(map[string]string)
+		s.categoryOverrides[override.HouseholdId] = overrides
+	}
+	overrides[override.ItemName] = override.Category
+
+	return nil
+}
+
+func (s *Store) DeleteCategoryOverride(householdId, itemName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	overrides := s.categoryOverrides[householdId]
+	if _, ok := overrides[itemName]; !ok {
+		return fmt.Errorf("category override %w", proxy.ErrNotFound)
+	}
+	delete(overrides, itemName)
+
+	return nil
+}
+
+// Store Preference Methods
+
+// ListStoreRules returns the household's category rules ordered by category
+func (s *Store) ListStoreRules(householdId string) ([]models.StoreRule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rules := make([]models.StoreRule, 0)
+	for category, store := range s.storeRules[householdId] {
+		rules = append(rules, models.StoreRule{HouseholdId: householdId, Category: category, Store: store})
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Category < rules[j].Category
+	})
+
+	return rules, nil
+}
+
+// SaveStoreRule creates the rule or replaces the store of an existing one for
+// the same category
+func (s *Store) SaveStoreRule(rule models.StoreRule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.households[rule.HouseholdId]; !ok {
+		return fmt.Errorf("household %w", proxy.ErrNotFound)
+	}
+
+	rules, ok := s.storeRules[rule.HouseholdId]
+	if !ok {
+		rules = make(map[string]models.StorePreference)
+		s.storeRules[rule.HouseholdId] = rules
+	}
+	rules[rule.Category] = rule.Store
+
+	return nil
+}
+
+func (s *Store) DeleteStoreRule(householdId, category string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rules := s.storeRules[householdId]
+	if _, ok := rules[category]; !ok {
+		return fmt.Errorf("store rule %w", proxy.ErrNotFound)
+	}
+	delete(rules, category)
+
+	return nil
+}
+
+// ListStoreHistory returns the household's store choices, most used first
+func (s *Store) ListStoreHistory(householdId string) ([]models.StoreChoice, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	choices := make([]models.StoreChoice, 0)
+	for itemName, stores := range s.storeHistory[householdId] {
+		for store, uses := range stores {
+			choices = append(choices, models.StoreChoice{HouseholdId: householdId, ItemName: itemName, Store: store, Uses: uses})
+		}
+	}
+	sort.SliceStable(choices, func(i, j int) bool {
+		if choices[i].Uses != choices[j].Uses {
+			return choices[i].Uses > choices[j].Uses
+		}
+		if choices[i].ItemName != choices[j].ItemName {
+			return choices[i].ItemName < choices[j].ItemName
+		}
+		return choices[i].Store < choices[j].Store
+	})
+
+	return choices, nil
+}
+
+// RecordStoreChoice adds one use of the store for the item
+func (s *Store) RecordStoreChoice(householdId, itemName string, store models.StorePreference) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.households[householdId]; !ok {
+		return fmt.Errorf("failed to record store choice: household %w", proxy.ErrNotFound)
+	}
+
+	items, ok := s.storeHistory[householdId]
+	if !ok {
+		items = make(map[string]map[models.StorePreference]int)
+		s.storeHistory[householdId] = items
+	}
+	stores, ok := items[itemName]
+	if !ok {
+		stores = make(map[models.StorePreference]int)
+		items[itemName] = stores
+	}
+	stores[store]++
+
+	return nil
+}
+
+// Price Methods
+
+// SaveStorePrices upserts the prices. A price older than the one already stored
+// for the item and store is ignored.
+func (s *Store) SaveStorePrices(storePrices []models.StoreData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, storePrice := range storePrices {
+		stores, ok := s.storePrices[storePrice.ItemName]
+		if !ok {
+			stores = make(map[models.StorePreference]models.StoreData)
+			s.storePrices[storePrice.ItemName] = stores
+		}
+		if existing, ok := stores[storePrice.StoreName]; ok && storePrice.LastUpdated.Before(existing.LastUpdated) {
+			continue
+		}
+		stores[storePrice.StoreName] = storePrice
+	}
+
+	return nil
+}
+
+func (s *Store) ListStorePrices() ([]models.StoreData, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	storePrices := make([]models.StoreData, 0)
+	for _, stores := range s.storePrices {
+		for _, storePrice := range stores {
+			storePrices = append(storePrices, storePrice)
+		}
+	}
+	sort.SliceStable(storePrices, func(i, j int) bool {
+		if storePrices[i].ItemName != storePrices[j].ItemName {
+			return storePrices[i].ItemName < storePrices[j].ItemName
+		}
+		return storePrices[i].StoreName < storePrices[j].StoreName
+	})
+
+	return storePrices, nil
+}
+
+// SavePriceChecks replaces the last check of each item at each store
+func (s *Store) SavePriceChecks(checks []models.PriceCheck) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, check := range checks {
+		stores, ok := s.priceChecks[check.ItemName]
+		if !ok {
+			stores = make(map[models.StorePreference]models.PriceCheck)
+			s.priceChecks[check.ItemName] = stores
+		}
+		stores[check.StoreName] = check
+	}
+
+	return nil
+}
+
+func (s *Store) ListPriceChecks() ([]models.PriceCheck, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	checks := make([]models.PriceCheck, 0)
+	for _, stores := range s.priceChecks {
+		for _, check := range stores {
+			checks = append(checks, check)
+		}
+	}
+	sort.SliceStable(checks, func(i, j int) bool {
+		if checks[i].ItemName != checks[j].ItemName {
+			return checks[i].ItemName < checks[j].ItemName
+		}
+		return checks[i].StoreName < checks[j].StoreName
+	})
+
+	return checks, nil
+}
+
+// Recipe Import Job Methods
+
+// CreateRecipeImportJob queues the job, setting its id, state and timestamps
+func (s *Store) CreateRecipeImportJob(job models.RecipeImportJob) (*models.RecipeImportJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.households[job.HouseholdId]; !ok {
+		return nil, fmt.Errorf("household %w", proxy.ErrNotFound)
+	}
+	if _, ok := s.groceryLists[job.ListId]; !ok {
+		return nil, fmt.Errorf("grocery list %w", proxy.ErrNotFound)
+	}
+
+	job.Id = newId()
+	job.State = models.JobQueued
+	job.CreatedItemIds = make([]string, 0)
+	job.UpdatedItemIds = make([]string, 0)
+	job.CreatedAt = time.Now().UTC()
+	job.UpdatedAt = job.CreatedAt
+	s.recipeImportJobs[job.Id] = job
+
+	return &job, nil
+}
+
+func (s *Store) GetRecipeImportJob(id string) (*models.RecipeImportJob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, ok := s.recipeImportJobs[id]
+	if !ok {
+		return nil, fmt.Errorf("recipe import job %w", proxy.ErrNotFound)
+	}
+
+	return &job, nil
+}
+
+// ClaimRecipeImportJob marks the oldest Queued job as Running and returns it.
+// Running jobs last updated before staleBefore were abandoned by a worker and
+// are claimed again.
+func (s *Store) ClaimRecipeImportJob(staleBefore time.Time) (*models.RecipeImportJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var claimed *models.RecipeImportJob
+	for _, job := range s.recipeImportJobs {
+		claimable := job.State == models.JobQueued || (job.State == models.JobRunning && job.UpdatedAt.Before(staleBefore))
+		if !claimable {
+			continue
+		}
+		if claimed == nil || job.CreatedAt.Before(claimed.CreatedAt) || (job.CreatedAt.Equal(claimed.CreatedAt) && job.Id < claimed.Id) {
+			job := job
+			claimed = &job
+		}
+	}
+	if claimed == nil {
+		return nil, fmt.Errorf("recipe import job %w", proxy.ErrNotFound)
+	}
+
+	claimed.State = models.JobRunning
+	claimed.LeaseId = newId()
+	claimed.UpdatedAt = time.Now().UTC()
+	s.recipeImportJobs[claimed.Id] = *claimed
+
+	return claimed, nil
+}
+
+// RenewRecipeImportJob keeps the job from being claimed again while its worker
+// is still running it
+func (s *Store) RenewRecipeImportJob(job models.RecipeImportJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.leasedRecipeImportJob(job)
+	if err != nil {
+		return err
+	}
+
+	existing.UpdatedAt = time.Now().UTC()
+	s.recipeImportJobs[job.Id] = existing
+
+	return nil
+}
+
+// FinishRecipeImportJob marks the job Done and saves its result, as long as
+// the job is still held by the lease it was claimed with
+func (s *Store) FinishRecipeImportJob(job models.RecipeImportJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.leasedRecipeImportJob(job)
+	if err != nil {
+		return err
+	}
+
+	existing.State = models.JobDone
+	existing.Status = job.Status
+	existing.Ingredients = job.Ingredients
+	existing.Error = job.Error
+	existing.CreatedItemIds = append(make([]string, 0), job.CreatedItemIds...)
+	existing.UpdatedItemIds = append(make([]string, 0), job.UpdatedItemIds...)
+	existing.UpdatedAt = time.Now().UTC()
+	s.recipeImportJobs[job.Id] = existing
+
+	return nil
+}
+
+// leasedRecipeImportJob returns the stored job if it is still Running under
+// the job's lease
+func (s *Store) leasedRecipeImportJob(job models.RecipeImportJob) (models.RecipeImportJob, error) {
+	existing, ok := s.recipeImportJobs[job.Id]
+	if !ok {
+		return existing, fmt.Errorf("recipe import job %w", proxy.ErrNotFound)
+	}
+	if existing.State != models.JobRunning || existing.LeaseId != job.LeaseId {
+		return existing, proxy.ErrLeaseLost
+	}
+
+	return existing, nil
+}
+
+// Saved Recipe Methods
+
+// CreateSavedRecipe adds the recipe to its household's library, setting its
+// id and timestamps
+func (s *Store) CreateSavedRecipe(recipe models.SavedRecipe) (*models.SavedRecipe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.households[recipe.HouseholdId]; !ok {
+		return nil, fmt.Errorf("household %w", proxy.ErrNotFound)
+	}
+
+	recipe.Id = newId()
+	recipe.Ingredients = append(make([]models.RecipeIngredient, 0), recipe.Ingredients...)
+	recipe.CreatedAt = time.Now().UTC()
+	recipe.UpdatedAt = recipe.CreatedAt
+	s.savedRecipes[recipe.Id] = recipe
+
+	return &recipe, nil
+}
+
+func (s *Store) GetSavedRecipe(id string) (*models.SavedRecipe, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	recipe, ok := s.savedRecipes[id]
+	if !ok {
+		return nil, fmt.Errorf("saved recipe %w", proxy.ErrNotFound)
+	}
+
+	recipe.Ingredients = append(make([]models.RecipeIngredient, 0), recipe.Ingredients...)
+	return &recipe, nil
+}
+
+// ListSavedRecipes returns the household's recipes by title
+func (s *Store) ListSavedRecipes(householdId string) ([]models.SavedRecipe, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	recipes := make([]models.SavedRecipe, 0)
+	for _, recipe := range s.savedRecipes {
+		if recipe.HouseholdId == householdId {
+			recipe.Ingredients = append(make([]models.RecipeIngredient, 0), recipe.Ingredients...)
+			recipes = append(recipes, recipe)
+		}
+	}
+	sort.Slice(recipes, func(i, j int) bool {
+		if recipes[i].Title != recipes[j].Title {
+			return recipes[i].Title < recipes[j].Title
+		}
+		return recipes[i].Id < recipes[j].Id
+	})
+
+	return recipes, nil
+}
+
+func (s *Store) UpdateSavedRecipe(id string, patch models.SavedRecipePatch) (*models.SavedRecipe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recipe, ok := s.savedRecipes[id]
+	if !ok {
+		return nil, fmt.Errorf("saved recipe %w", proxy.ErrNotFound)
+	}
+
+	if patch.Title != nil {
+		recipe.Title = *patch.Title
+	}
+	if patch.Servings != nil {
+		recipe.Servings = *patch.Servings
+	}
+	if patch.Ingredients != nil {
+		recipe.Ingredients = append(make([]models.RecipeIngredient, 0), *patch.Ingredients...)
+	}
+	recipe.UpdatedAt = time.Now().UTC()
+	s.savedRecipes[id] = recipe
+
+	recipe.Ingredients = append(make([]models.RecipeIngredient, 0), recipe.Ingredients...)
+	return &recipe, nil
+}
+
+func (s *Store) DeleteSavedRecipe(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.savedRecipes[id]; !ok {
+		return fmt.Errorf("saved recipe %w", proxy.ErrNotFound)
+	}
+
+	delete(s.savedRecipes, id)
+
+	return nil
+}
+
+func (s *Store) GetTaskSchedule(taskIds []string) ([]models.TaskScheduleItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	items := make([]models.TaskScheduleItem, 0)
+	for _, taskId := range taskIds {
+		for _, date := range s.schedules[taskId] {
+			items = append(items, models.TaskScheduleItem{TaskId: taskId, Date: date})
+		}
+	}
+
+	return items, nil
+}
+
+func (s *Store) CreateTaskSchedule(taskId string, dates []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.groceryItems[taskId]; !ok {
+		return fmt.Errorf("failed to scheduled item: grocery item %w", proxy.ErrNotFound)
+	}
+
+	s.schedules[taskId] = append([]string(nil), dates...)
+
+	return nil
+}
+
+func (s *Store) Close() error {
+	return nil
+}
+
+func sortHouseholds(households []models.Household) {
+	sort.SliceStable(households, func(i, j int) bool {
+		return households[i].Name < households[j].Name
+	})
+}
+
+func sortUsers(users []models.User) {
+	sort.SliceStable(users, func(i, j int) bool {
+		return users[i].Name < users[j].Name
+	})
+}
@@ -0,0 +1,13 @@
+package memory
+
+import (
+	"api/proxy"
+	"api/proxy/storetest"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) proxy.Store {
+		return NewStore()
+	})
+}
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"api/proxy"
+	"embed"
+	"fmt"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockId is the advisory lock key replicas take while migrating, so
+// that instances starting together apply each migration exactly once
+const migrationLockId = 57457
+
+// Migrate applies every embedded migration that has not yet been recorded in
+// schema_migrations. Each migration runs in its own transaction while holding
+// an advisory lock, so concurrent replicas wait for each other.
+func (db *DB) Migrate() error {
+	if err := db.createMigrationsTable(); err != nil {
+		return err
+	}
+
+	migrations, err := proxy.LoadMigrations(migrationFiles, "migrations")
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if err := db.applyMigration(m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *DB) createMigrationsTable() error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin creating schema_migrations: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", migrationLockId); err != nil {
+		return fmt.Errorf("failed to take migration lock: %w", err)
+	}
+
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TEXT NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (db *DB) applyMigration(m proxy.Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %d: %w", m.Version, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", migrationLockId); err != nil {
+		return fmt.Errorf("failed to take migration lock: %w", err)
+	}
+
+	var applied int
+	err = tx.QueryRow("SELECT COUNT(*) FROM schema_migrations WHERE version = $1", m.Version).Scan(&applied)
+	if err != nil {
+		return fmt.Errorf("failed to check migration %d: %w", m.Version, err)
+	}
+	if applied > 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(m.SQL); err != nil {
+		return fmt.Errorf("failed to apply migration %d_%s: %w", m.Version, m.Name, err)
+	}
+
+	_, err = tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)",
+		m.Version, m.Name, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", m.Version, err)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,41 @@
+-- Baseline schema, equivalent to proxy/sqlite/migrations/0001_init.sql.
+-- Ids default to 32 lowercase hex characters like the sqlite triggers produce.
+
+CREATE TABLE IF NOT EXISTS households (
+    id TEXT PRIMARY KEY DEFAULT replace(gen_random_uuid()::text, '-', ''),
+    name TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id TEXT PRIMARY KEY DEFAULT replace(gen_random_uuid()::text, '-', ''),
+    name TEXT
+);
+
+CREATE TABLE IF NOT EXISTS household_users (
+    household_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    PRIMARY KEY (household_id, user_id),
+    FOREIGN KEY (household_id) REFERENCES households(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS grocery_items (
+    id TEXT PRIMARY KEY DEFAULT replace(gen_random_uuid()::text, '-', ''),
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    category TEXT,
+    checked BOOLEAN DEFAULT FALSE,
+    household_id TEXT NOT NULL,
+    FOREIGN KEY (household_id) REFERENCES households(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS scheduled_items (
+    task_id TEXT NOT NULL,
+    date TEXT NOT NULL,
+    FOREIGN KEY (task_id) REFERENCES grocery_items(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_household_users_household_id ON household_users(household_id);
+CREATE INDEX IF NOT EXISTS idx_household_users_user_id ON household_users(user_id);
+CREATE INDEX IF NOT EXISTS idx_grocery_items_household_id ON grocery_items(household_id);
+CREATE INDEX IF NOT EXISTS idx_scheduled_items_task_id ON scheduled_items(task_id);
@@ -0,0 +1 @@
+ALTER TABLE grocery_items ADD COLUMN IF NOT EXISTS store_override TEXT;
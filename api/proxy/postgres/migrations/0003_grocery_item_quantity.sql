@@ -0,0 +1,2 @@
+ALTER TABLE grocery_items ADD COLUMN IF NOT EXISTS quantity DOUBLE PRECISION;
+ALTER TABLE grocery_items ADD COLUMN IF NOT EXISTS unit TEXT;
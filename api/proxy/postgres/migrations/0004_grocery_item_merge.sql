@@ -0,0 +1,3 @@
+-- JSON encoded []models.Quantity and []string
+ALTER TABLE grocery_items ADD COLUMN IF NOT EXISTS extra_quantities TEXT;
+ALTER TABLE grocery_items ADD COLUMN IF NOT EXISTS sources TEXT;
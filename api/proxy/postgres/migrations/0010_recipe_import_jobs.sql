@@ -0,0 +1,22 @@
+-- Recipe imports run in the background. A Running job whose updated_at is
+-- older than the worker lease was interrupted, e.g. by a restart, and is
+-- picked up again.
+CREATE TABLE IF NOT EXISTS recipe_import_jobs (
+    id TEXT PRIMARY KEY,
+    household_id TEXT NOT NULL,
+    list_id TEXT NOT NULL,
+    item_id TEXT,
+    url TEXT NOT NULL,
+    state TEXT NOT NULL,
+    status TEXT,
+    ingredients INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    created_item_ids TEXT,
+    updated_item_ids TEXT,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (household_id) REFERENCES households(id) ON DELETE CASCADE,
+    FOREIGN KEY (list_id) REFERENCES grocery_lists(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_import_jobs_state ON recipe_import_jobs(state, created_at);
@@ -0,0 +1 @@
+ALTER TABLE households ADD COLUMN IF NOT EXISTS measurement_system TEXT;
@@ -0,0 +1,10 @@
+-- When the price refresh last asked a store for an item, whether or not the
+-- store had a price for it, so that items a store doesn't sell and prices
+-- that say they are old aren't fetched again on every run
+CREATE TABLE IF NOT EXISTS price_checks (
+    item_name TEXT NOT NULL,
+    store_name TEXT NOT NULL,
+    checked_at TIMESTAMPTZ NOT NULL,
+    found BOOLEAN NOT NULL,
+    PRIMARY KEY (item_name, store_name)
+);
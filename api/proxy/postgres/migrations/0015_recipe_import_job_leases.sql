@@ -0,0 +1,3 @@
+-- Each claim of a job gets a new lease id, so a worker whose lease ran out
+-- can tell that another worker claimed the job and must not finish it.
+ALTER TABLE recipe_import_jobs ADD COLUMN IF NOT EXISTS lease_id TEXT;
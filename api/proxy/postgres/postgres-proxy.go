@@ -0,0 +1,1427 @@
+package postgres
+
+import (
+	"api/models"
+	"api/proxy"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type DB struct {
+	*sql.DB
+	// tx is set on the DB a Transaction passes to its function, and every
+	// statement then runs in it
+	tx *sql.Tx
+}
+
+var _ proxy.Store = (*DB)(nil)
+
+// NewDB opens the pool shared by every request. Unlike sqlite the database
+// can be shared by several API replicas.
+func NewDB(databaseUrl string) (*DB, error) {
+	postgresDB, err := sql.Open("postgres", databaseUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// Check if database can be accessed
+	if err := postgresDB.Ping(); err != nil {
+		postgresDB.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return &DB{DB: postgresDB}, nil
+}
+
+// Transaction runs fn on a DB whose statements all run in one transaction.
+// Transactions read committed data, so ones that decide what to write from
+// what they read lock it first, e.g. with LockGroceryList.
+func (db *DB) Transaction(fn func(tx proxy.Store) error) error {
+	if db.tx != nil {
+		return fn(db)
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(&DB{DB: db.DB, tx: tx}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// transaction is what Begin returns, so the methods that need several
+// statements to succeed together work the same in a Transaction
+type transaction interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+	Prepare(query string) (*sql.Stmt, error)
+	Commit() error
+	Rollback() error
+}
+
+// enclosedTx is the transaction of a Transaction as seen by a method run in
+// it. Only the Transaction commits or rolls it back, so a method's error has
+// to be returned from fn for its statements to be undone.
+type enclosedTx struct {
+	*sql.Tx
+}
+
+func (enclosedTx) Commit() error {
+	return nil
+}
+
+func (enclosedTx) Rollback() error {
+	return nil
+}
+
+// Begin starts a transaction, or carries on with the one of a Transaction
+func (db *DB) Begin() (transaction, error) {
+	if db.tx != nil {
+		return enclosedTx{db.tx}, nil
+	}
+
+	return db.DB.Begin()
+}
+
+// Exec, Query and QueryRow run the statement in the DB's transaction, if it
+// is in one
+func (db *DB) Exec(query string, args ...any) (sql.Result, error) {
+	if db.tx != nil {
+		return db.tx.Exec(query, args...)
+	}
+
+	return db.DB.Exec(query, args...)
+}
+
+func (db *DB) Query(query string, args ...any) (*sql.Rows, error) {
+	if db.tx != nil {
+		return db.tx.Query(query, args...)
+	}
+
+	return db.DB.Query(query, args...)
+}
+
+func (db *DB) QueryRow(query string, args ...any) *sql.Row {
+	if db.tx != nil {
+		return db.tx.QueryRow(query, args...)
+	}
+
+	return db.DB.QueryRow(query, args...)
+}
+
+// Household Methods
+func (db *DB) CreateHousehold(name string) (*models.Household, error) {
+	uuidv7, _ := uuid.NewV7()
+	id := uuidv7.String()
+
+	if err := db.createHousehold(id, name); err != nil {
+		return nil, err
+	}
+
+	return &models.Household{Id: id, Name: name}, nil
+}
+
+func (db *DB) CreateUserHousehold(id string) (*models.Household, error) {
+	if err := db.createHousehold(id, id); err != nil {
+		return nil, err
+	}
+
+	return &models.Household{Id: id, Name: id}, nil
+}
+
+// createHousehold inserts the household together with its default list
+func (db *DB) createHousehold(id, name string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin household creation: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("INSERT INTO households (id, name) VALUES ($1, $2)", id, name); err != nil {
+		return fmt.Errorf("failed to create household: %w", err)
+	}
+
+	_, err = tx.Exec("INSERT INTO grocery_lists (id, household_id, name) VALUES ($1, $2, $3)", id, id, models.DefaultGroceryListName)
+	if err != nil {
+		return fmt.Errorf("failed to create default grocery list: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (db *DB) GetHousehold(id string) (*models.Household, error) {
+	var household models.Household
+	err := db.QueryRow("SELECT id, name, COALESCE(measurement_system, '') FROM households WHERE id = $1", id).Scan(&household.Id, &household.Name, &household.MeasurementSystem)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("household %w", proxy.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get household: %w", err)
+	}
+
+	return &household, nil
+}
+
+func (db *DB) UpdateHousehold(id, name string) error {
+	result, err := db.Exec("UPDATE households SET name = $1 WHERE id = $2", name, id)
+	if err != nil {
+		return fmt.Errorf("failed to update household: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("household %w", proxy.ErrNotFound)
+	}
+
+	return nil
+}
+
+// SetHouseholdMeasurementSystem saves the system recipe quantities are
+// converted to, the empty system keeping them as written
+func (db *DB) SetHouseholdMeasurementSystem(id string, system models.MeasurementSystem) error {
+	result, err := db.Exec("UPDATE households SET measurement_system = $1 WHERE id = $2", system, id)
+	if err != nil {
+		return fmt.Errorf("failed to update household measurement system: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("household %w", proxy.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (db *DB) DeleteHousehold(id string) error {
+	result, err := db.Exec("DELETE FROM households WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete household: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("household %w", proxy.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (db *DB) ListHouseholds() ([]models.Household, error) {
+	rows, err := db.Query("SELECT id, name, COALESCE(measurement_system, '') FROM households ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list households: %w", err)
+	}
+	defer rows.Close()
+
+	var households []models.Household
+	for rows.Next() {
+		var h models.Household
+		if err := rows.Scan(&h.Id, &h.Name, &h.MeasurementSystem); err != nil {
+			return nil, fmt.Errorf("failed to scan household row: %w", err)
+		}
+		households = append(households, h)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in rows iteration: %w", err)
+	}
+
+	return households, nil
+}
+
+func (db *DB) CreateUser(name string) (*models.User, error) {
+	var id string
+	err := db.QueryRow("INSERT INTO users (name) VALUES ($1) RETURNING id", name).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return &models.User{Id: id, Name: name}, nil
+}
+
+func (db *DB) GetUser(id string) (*models.User, error) {
+	var user models.User
+	var name sql.NullString
+	err := db.QueryRow("SELECT id, name FROM users WHERE id = $1", id).Scan(&user.Id, &name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("user %w", proxy.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if name.Valid {
+		user.Name = name.String
+	}
+
+	return &user, nil
+}
+
+func (db *DB) UpdateUser(id string, name string) error {
+	result, err := db.Exec("UPDATE users SET name = $1 WHERE id = $2", name, id)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("user %w", proxy.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (db *DB) DeleteUser(id string) error {
+	result, err := db.Exec("DELETE FROM users WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("user %w", proxy.ErrNotFound)
+	}
+
+	return nil
+}
+
+// ListUsers returns all users
+func (db *DB) ListUsers() ([]models.User, error) {
+	rows, err := db.Query("SELECT id, name FROM users ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var u models.User
+		var name sql.NullString
+		if err := rows.Scan(&u.Id, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan user row: %w", err)
+		}
+		if name.Valid {
+			u.Name = name.String
+		}
+		users = append(users, u)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in rows iteration: %w", err)
+	}
+
+	return users, nil
+}
+
+// Household-User Methods
+func (db *DB) AddUserToHousehold(userId, householdId string) error {
+	// First check if the user and household exist
+	if _, err := db.GetUser(userId); err != nil {
+		return err
+	}
+	if _, err := db.GetHousehold(householdId); err != nil {
+		return err
+	}
+
+	_, err := db.Exec("INSERT INTO household_users (household_id, user_id) VALUES ($1, $2)",
+		householdId, userId)
+	if err != nil {
+		return fmt.Errorf("failed to add user to household: %w", err)
+	}
+	return nil
+}
+
+// RemoveUserFromHousehold dissociates a user from a household
+func (db *DB) RemoveUserFromHousehold(userId, householdId string) error {
+	result, err := db.Exec("DELETE FROM household_users WHERE household_id = $1 AND user_id = $2",
+		householdId, userId)
+	if err != nil {
+		return fmt.Errorf("failed to remove user from household: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("user %w in household", proxy.ErrNotFound)
+	}
+
+	return nil
+}
+
+// GetHouseholdUsers returns all users in a household
+func (db *DB) GetHouseholdUsers(householdId string) ([]models.User, error) {
+	// First check if the household exists
+	if _, err := db.GetHousehold(householdId); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT u.id, u.name 
+		FROM users u
+		JOIN household_users hu ON u.id = hu.user_id
+		WHERE hu.household_id = $1
+		ORDER BY u.name
+	`
+	rows, err := db.Query(query, householdId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get household users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var u models.User
+		var name sql.NullString
+		if err := rows.Scan(&u.Id, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan user row: %w", err)
+		}
+		if name.Valid {
+			u.Name = name.String
+		}
+		users = append(users, u)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in rows iteration: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetUserHouseholds returns all households a user belongs to
+func (db *DB) GetUserHouseholds(userId string) ([]models.Household, error) {
+	// First check if the user exists
+	if _, err := db.GetUser(userId); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT h.id, h.name, COALESCE(h.measurement_system, '')
+		FROM households h
+		JOIN household_users hu ON h.id = hu.household_id
+		WHERE hu.user_id = $1
+		ORDER BY h.name
+	`
+	rows, err := db.Query(query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user households: %w", err)
+	}
+	defer rows.Close()
+
+	var households []models.Household
+	for rows.Next() {
+		var h models.Household
+		if err := rows.Scan(&h.Id, &h.Name, &h.MeasurementSystem); err != nil {
+			return nil, fmt.Errorf("failed to scan household row: %w", err)
+		}
+		households = append(households, h)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in rows iteration: %w", err)
+	}
+
+	return households, nil
+}
+
+// Grocery List Methods
+
+// CreateGroceryList adds a list to a household, keeping its Id if it already has one
+func (db *DB) CreateGroceryList(list models.GroceryList) (*models.GroceryList, error) {
+	if _, err := db.GetHousehold(list.HouseholdId); err != nil {
+		return nil, err
+	}
+
+	list.GetOrGenerateID()
+
+	_, err := db.Exec("INSERT INTO grocery_lists (id, household_id, name) VALUES ($1, $2, $3)", list.Id, list.HouseholdId, list.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create grocery list: %w", err)
+	}
+
+	return &list, nil
+}
+
+func (db *DB) GetGroceryList(id string) (*models.GroceryList, error) {
+	var list models.GroceryList
+	err := db.QueryRow("SELECT id, household_id, name FROM grocery_lists WHERE id = $1", id).Scan(&list.Id, &list.HouseholdId, &list.Name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("grocery list %w", proxy.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get grocery list: %w", err)
+	}
+
+	return &list, nil
+}
+
+// LockGroceryList locks the list's row until the transaction ends. Outside a
+// Transaction the lock is let go straight away.
+func (db *DB) LockGroceryList(id string) error {
+	var locked string
+	err := db.QueryRow("SELECT id FROM grocery_lists WHERE id = $1 FOR UPDATE", id).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("grocery list %w", proxy.ErrNotFound)
+		}
+		return fmt.Errorf("failed to lock grocery list: %w", err)
+	}
+
+	return nil
+}
+
+// ListGroceryLists returns the household's lists, default list first
+func (db *DB) ListGroceryLists(householdId string) ([]models.GroceryList, error) {
+	rows, err := db.Query("SELECT id, household_id, name FROM grocery_lists WHERE household_id = $1 ORDER BY id = household_id DESC, name", householdId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list grocery lists: %w", err)
+	}
+	defer rows.Close()
+
+	lists := make([]models.GroceryList, 0)
+	for rows.Next() {
+		var list models.GroceryList
+		if err := rows.Scan(&list.Id, &list.HouseholdId, &list.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan grocery list: %w", err)
+		}
+		lists = append(lists, list)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in rows iteration: %w", err)
+	}
+
+	return lists, nil
+}
+
+func (db *DB) RenameGroceryList(id, name string) error {
+	result, err := db.Exec("UPDATE grocery_lists SET name = $1 WHERE id = $2", name, id)
+	if err != nil {
+		return fmt.Errorf("failed to rename grocery list: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("grocery list %w", proxy.ErrNotFound)
+	}
+
+	return nil
+}
+
+// DeleteGroceryList deletes the list along with its items and layout
+func (db *DB) DeleteGroceryList(id string) error {
+	result, err := db.Exec("DELETE FROM grocery_lists WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete grocery list: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("grocery list %w", proxy.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Grocery Item Methods
+
+// CreateGroceryItem adds a new grocery item, keeping its Id if it already has
+// one. Items without a ListId go on the household's default list.
+func (db *DB) CreateGroceryItem(item models.GroceryItem) (*models.GroceryItem, error) {
+	// First check if the household exists
+	if _, err := db.GetHousehold(item.HouseholdId); err != nil {
+		return nil, err
+	}
+
+	item.GetOrGenerateID()
+	if item.ListId == "" {
+		item.ListId = item.HouseholdId
+	}
+	if _, err := db.GetGroceryList(item.ListId); err != nil {
+		return nil, err
+	}
+
+	_, err := db.Exec("INSERT INTO grocery_items (id, name, kind, category, store_override, household_id, list_id, checked, quantity, unit, extra_quantities, sources) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)",
+		item.Id, item.Name, item.Kind, item.Category, item.StoreOverride, item.HouseholdId, item.ListId, item.Checked, item.Quantity, item.Unit,
+		encodeJSON(item.ExtraQuantities), encodeJSON(item.Sources))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create grocery item: %w", err)
+	}
+
+	return &item, nil
+}
+
+// GetGroceryItem retrieves a grocery item by Id
+func (db *DB) GetGroceryItem(id string) (*models.GroceryItem, error) {
+	item, err := scanGroceryItem(db.QueryRow("SELECT "+groceryItemColumns+" FROM grocery_items WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("grocery item %w", proxy.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get grocery item: %w", err)
+	}
+
+	return &item, nil
+}
+
+// UpdateGroceryItem updates a grocery item
+func (db *DB) UpdateGroceryItemStatus(id string, checked bool) error {
+	result, err := db.Exec("UPDATE grocery_items SET checked = $1 WHERE id = $2",
+		checked, id)
+	if err != nil {
+		return fmt.Errorf("failed to update grocery item: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("grocery item %w", proxy.ErrNotFound)
+	}
+
+	return nil
+}
+
+// UpdateGroceryItem writes the fields set in the patch and returns the updated item
+func (db *DB) UpdateGroceryItem(id string, patch models.GroceryItemPatch) (*models.GroceryItem, error) {
+	var assignments []string
+	var args []interface{}
+	set := func(column string, value interface{}) {
+		args = append(args, value)
+		assignments = append(assignments, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if patch.Name != nil {
+		set("name", *patch.Name)
+	}
+	if patch.Kind != nil {
+		set("kind", *patch.Kind)
+	}
+	if patch.Category != nil {
+		set("category", *patch.Category)
+	}
+	if patch.StoreOverride != nil {
+		set("store_override", *patch.StoreOverride)
+	}
+	if patch.Checked != nil {
+		set("checked", *patch.Checked)
+	}
+	if patch.Quantity != nil {
+		set("quantity", *patch.Quantity)
+	}
+	if patch.Unit != nil {
+		set("unit", *patch.Unit)
+	}
+	if patch.ExtraQuantities != nil {
+		set("extra_quantities", encodeJSON(*patch.ExtraQuantities))
+	}
+	if patch.Sources != nil {
+		set("sources", encodeJSON(*patch.Sources))
+	}
+	if patch.ListId != nil {
+		if _, err := db.GetGroceryList(*patch.ListId); err != nil {
+			return nil, err
+		}
+		set("list_id", *patch.ListId)
+	}
+
+	if len(assignments) == 0 {
+		return db.GetGroceryItem(id)
+	}
+
+	args = append(args, id)
+	query := fmt.Sprintf("UPDATE grocery_items SET %s WHERE id = $%d", strings.Join(assignments, ", "), len(args))
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update grocery item: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return nil, fmt.Errorf("grocery item %w", proxy.ErrNotFound)
+	}
+
+	return db.GetGroceryItem(id)
+}
+
+func (db *DB) DeleteGroceryItems(ids []string) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("no Ids provided")
+	}
+
+	result, err := db.Exec("DELETE FROM grocery_items WHERE id = ANY($1)", pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to delete grocery items: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("grocery items to delete %w", proxy.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (db *DB) ListGroceryItemsByHousehold(householdId string) ([]models.GroceryItem, error) {
+	if _, err := db.GetHousehold(householdId); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query("SELECT "+groceryItemColumns+" FROM grocery_items WHERE household_id = $1 ORDER BY name", householdId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list grocery items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]models.GroceryItem, 0)
+	for rows.Next() {
+		i, err := scanGroceryItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan grocery item row: %w", err)
+		}
+		items = append(items, i)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in rows iteration: %w", err)
+	}
+
+	return items, nil
+}
+
+func (db *DB) ListGroceryItemsByList(listId string) ([]models.GroceryItem, error) {
+	if _, err := db.GetGroceryList(listId); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query("SELECT "+groceryItemColumns+" FROM grocery_items WHERE list_id = $1 ORDER BY name", listId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list grocery items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]models.GroceryItem, 0)
+	for rows.Next() {
+		i, err := scanGroceryItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan grocery item row: %w", err)
+		}
+		items = append(items, i)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in rows iteration: %w", err)
+	}
+
+	return items, nil
+}
+
+func (db *DB) GetTaskSchedule(taskIds []string) ([]models.TaskScheduleItem, error) {
+	if len(taskIds) == 0 {
+		return []models.TaskScheduleItem{}, nil
+	}
+
+	rows, err := db.Query("SELECT task_id, date FROM scheduled_items WHERE task_id = ANY($1)", pq.Array(taskIds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule for task: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]models.TaskScheduleItem, 0)
+	for rows.Next() {
+		var item models.TaskScheduleItem
+		if err := rows.Scan(&item.TaskId, &item.Date); err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in row iteration: %w", err)
+	}
+
+	return items, nil
+}
+
+func (db *DB) CreateTaskSchedule(taskId string, dates []string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin schedule update: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM scheduled_items WHERE task_id = $1", taskId)
+	if err != nil {
+		return fmt.Errorf("failed to delete scheduled items: %w", err)
+	}
+
+	_, err = tx.Exec("INSERT INTO scheduled_items (task_id, date) SELECT $1, unnest($2::text[])", taskId, pq.Array(dates))
+	if err != nil {
+		return fmt.Errorf("failed to scheduled item: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// Layout Methods
+
+// GetLayout returns the list's saved layout, empty if it has none
+func (db *DB) GetLayout(listId string) ([]models.LayoutBlock, error) {
+	rows, err := db.Query("SELECT type, value FROM grocery_list_layout_blocks WHERE list_id = $1 ORDER BY position", listId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get layout: %w", err)
+	}
+	defer rows.Close()
+
+	layout := make([]models.LayoutBlock, 0)
+	for rows.Next() {
+		var block models.LayoutBlock
+		if err := rows.Scan(&block.Type, &block.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan layout block: %w", err)
+		}
+		layout = append(layout, block)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in rows iteration: %w", err)
+	}
+
+	return layout, nil
+}
+
+// SaveLayout replaces the list's layout
+func (db *DB) SaveLayout(listId string, layout []models.LayoutBlock) error {
+	if _, err := db.GetGroceryList(listId); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin layout update: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM grocery_list_layout_blocks WHERE list_id = $1", listId); err != nil {
+		return fmt.Errorf("failed to delete layout: %w", err)
+	}
+
+	for position, block := range layout {
+		_, err := tx.Exec("INSERT INTO grocery_list_layout_blocks (list_id, position, type, value) VALUES ($1, $2, $3, $4)",
+			listId, position, block.Type, block.Value)
+		if err != nil {
+			return fmt.Errorf("failed to save layout block: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Category Override Methods
+
+// ListCategoryOverrides returns the household's overrides ordered by item name
+func (db *DB) ListCategoryOverrides(householdId string) ([]models.CategoryOverride, error) {
+	rows, err := db.Query("SELECT household_id, item_name, category FROM category_overrides WHERE household_id = $1 ORDER BY item_name", householdId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list category overrides: %w", err)
+	}
+	defer rows.Close()
+
+	overrides := make([]models.CategoryOverride, 0)
+	for rows.Next() {
+		var override models.CategoryOverride
+		if err := rows.Scan(&override.HouseholdId, &override.ItemName, &override.Category); err != nil {
+			return nil, fmt.Errorf("failed to scan category override: %w", err)
+		}
+		overrides = append(overrides, override)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in rows iteration: %w", err)
+	}
+
+	return overrides, nil
+}
+
+// SaveCategoryOverride creates the override or replaces the category of an
+// existing one for the same item name
+func (db *DB) SaveCategoryOverride(override models.CategoryOverride) error {
+	if _, err := db.GetHousehold(override.HouseholdId); err != nil {
+		return err
+	}
+
+	_, err := db.Exec("INSERT INTO category_overrides (household_id, item_name, category) VALUES ($1, $2, $3) ON CONFLICT (household_id, item_name) DO UPDATE SET category = excluded.category",
+		override.HouseholdId, override.ItemName, override.Category)
+	if err != nil {
+		return fmt.Errorf("failed to save category override: %w", err)
+	}
+
+	return nil
+}
+
+func (db *DB) DeleteCategoryOverride(householdId, itemName string) error {
+	result, err := db.Exec("DELETE FROM category_overrides WHERE household_id = $1 AND item_name = $2", householdId, itemName)
+	if err != nil {
+		return fmt.Errorf("failed to delete category override: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("category override %w", proxy.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Store Preference Methods
+
+// ListStoreRules returns the household's category rules ordered by category
+func (db *DB) ListStoreRules(householdId string) ([]models.StoreRule, error) {
+	rows, err := db.Query("SELECT household_id, category, store FROM store_category_rules WHERE household_id = $1 ORDER BY category", householdId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list store rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]models.StoreRule, 0)
+	for rows.Next() {
+		var rule models.StoreRule
+		if err := rows.Scan(&rule.HouseholdId, &rule.Category, &rule.Store); err != nil {
+			return nil, fmt.Errorf("failed to scan store rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in rows iteration: %w", err)
+	}
+
+	return rules, nil
+}
+
+// SaveStoreRule creates the rule or replaces the store of an existing one for
+// the same category
+func (db *DB) SaveStoreRule(rule models.StoreRule) error {
+	if _, err := db.GetHousehold(rule.HouseholdId); err != nil {
+		return err
+	}
+
+	_, err := db.Exec("INSERT INTO store_category_rules (household_id, category, store) VALUES ($1, $2, $3) ON CONFLICT (household_id, category) DO UPDATE SET store = excluded.store",
+		rule.HouseholdId, rule.Category, rule.Store)
+	if err != nil {
+		return fmt.Errorf("failed to save store rule: %w", err)
+	}
+
+	return nil
+}
+
+func (db *DB) DeleteStoreRule(householdId, category string) error {
+	result, err := db.Exec("DELETE FROM store_category_rules WHERE household_id = $1 AND category = $2", householdId, category)
+	if err != nil {
+		return fmt.Errorf("failed to delete store rule: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("store rule %w", proxy.ErrNotFound)
+	}
+
+	return nil
+}
+
+// ListStoreHistory returns the household's store choices, most used first
+func (db *DB) ListStoreHistory(householdId string) ([]models.StoreChoice, error) {
+	rows, err := db.Query("SELECT household_id, item_name, store, uses FROM store_history WHERE household_id = $1 ORDER BY uses DESC, item_name, store", householdId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list store history: %w", err)
+	}
+	defer rows.Close()
+
+	choices := make([]models.StoreChoice, 0)
+	for rows.Next() {
+		var choice models.StoreChoice
+		if err := rows.Scan(&choice.HouseholdId, &choice.ItemName, &choice.Store, &choice.Uses); err != nil {
+			return nil, fmt.Errorf("failed to scan store choice: %w", err)
+		}
+		choices = append(choices, choice)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in rows iteration: %w", err)
+	}
+
+	return choices, nil
+}
+
+// RecordStoreChoice adds one use of the store for the item
+func (db *DB) RecordStoreChoice(householdId, itemName string, store models.StorePreference) error {
+	if _, err := db.GetHousehold(householdId); err != nil {
+		return fmt.Errorf("failed to record store choice: %w", err)
+	}
+
+	_, err := db.Exec("INSERT INTO store_history (household_id, item_name, store, uses) VALUES ($1, $2, $3, 1) ON CONFLICT (household_id, item_name, store) DO UPDATE SET uses = store_history.uses + 1",
+		householdId, itemName, store)
+	if err != nil {
+		return fmt.Errorf("failed to record store choice: %w", err)
+	}
+
+	return nil
+}
+
+// Price Methods
+
+// SaveStorePrices upserts the prices in one transaction. A price older than the
+// one already stored for the item and store is ignored.
+func (db *DB) SaveStorePrices(storePrices []models.StoreData) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin price import: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare("INSERT INTO store_prices (item_name, store_name, price, last_updated) VALUES ($1, $2, $3, $4) ON CONFLICT (item_name, store_name) DO UPDATE SET price = excluded.price, last_updated = excluded.last_updated WHERE excluded.last_updated >= store_prices.last_updated")
+	if err != nil {
+		return fmt.Errorf("failed to prepare price import: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, storePrice := range storePrices {
+		if _, err := stmt.Exec(storePrice.ItemName, storePrice.StoreName, storePrice.Price, storePrice.LastUpdated); err != nil {
+			return fmt.Errorf("failed to save price of %s at %s: %w", storePrice.ItemName, storePrice.StoreName, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (db *DB) ListStorePrices() ([]models.StoreData, error) {
+	rows, err := db.Query("SELECT item_name, store_name, price, last_updated FROM store_prices ORDER BY item_name, store_name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prices: %w", err)
+	}
+	defer rows.Close()
+
+	storePrices := make([]models.StoreData, 0)
+	for rows.Next() {
+		var storePrice models.StoreData
+		if err := rows.Scan(&storePrice.ItemName, &storePrice.StoreName, &storePrice.Price, &storePrice.LastUpdated); err != nil {
+			return nil, fmt.Errorf("failed to scan price: %w", err)
+		}
+		storePrices = append(storePrices, storePrice)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in rows iteration: %w", err)
+	}
+
+	return storePrices, nil
+}
+
+// SavePriceChecks replaces the last check of each item at each store in one
+// transaction
+func (db *DB) SavePriceChecks(checks []models.PriceCheck) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin saving price checks: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare("INSERT INTO price_checks (item_name, store_name, checked_at, found) VALUES ($1, $2, $3, $4) ON CONFLICT (item_name, store_name) DO UPDATE SET checked_at = excluded.checked_at, found = excluded.found")
+	if err != nil {
+		return fmt.Errorf("failed to prepare saving price checks: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, check := range checks {
+		if _, err := stmt.Exec(check.ItemName, check.StoreName, check.CheckedAt, check.Found); err != nil {
+			return fmt.Errorf("failed to save price check of %s at %s: %w", check.ItemName, check.StoreName, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (db *DB) ListPriceChecks() ([]models.PriceCheck, error) {
+	rows, err := db.Query("SELECT item_name, store_name, checked_at, found FROM price_checks ORDER BY item_name, store_name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list price checks: %w", err)
+	}
+	defer rows.Close()
+
+	checks := make([]models.PriceCheck, 0)
+	for rows.Next() {
+		var check models.PriceCheck
+		if err := rows.Scan(&check.ItemName, &check.StoreName, &check.CheckedAt, &check.Found); err != nil {
+			return nil, fmt.Errorf("failed to scan price check: %w", err)
+		}
+		checks = append(checks, check)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in rows iteration: %w", err)
+	}
+
+	return checks, nil
+}
+
+// Recipe Import Job Methods
+
+// CreateRecipeImportJob queues the job, setting its id, state and timestamps
+func (db *DB) CreateRecipeImportJob(job models.RecipeImportJob) (*models.RecipeImportJob, error) {
+	if _, err := db.GetHousehold(job.HouseholdId); err != nil {
+		return nil, err
+	}
+	if _, err := db.GetGroceryList(job.ListId); err != nil {
+		return nil, err
+	}
+
+	uuidv7, _ := uuid.NewV7()
+	job.Id = uuidv7.String()
+	job.State = models.JobQueued
+	job.CreatedItemIds = make([]string, 0)
+	job.UpdatedItemIds = make([]string, 0)
+	job.CreatedAt = time.Now().UTC()
+	job.UpdatedAt = job.CreatedAt
+
+	_, err := db.Exec("INSERT INTO recipe_import_jobs (id, household_id, list_id, item_id, url, servings, state, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		job.Id, job.HouseholdId, job.ListId, job.ItemId, job.Url, job.Servings, job.State, job.CreatedAt, job.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recipe import job: %w", err)
+	}
+
+	return &job, nil
+}
+
+func (db *DB) GetRecipeImportJob(id string) (*models.RecipeImportJob, error) {
+	job, err := scanRecipeImportJob(db.QueryRow("SELECT "+recipeImportJobColumns+" FROM recipe_import_jobs WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("recipe import job %w", proxy.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get recipe import job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// ClaimRecipeImportJob marks the oldest Queued job as Running under a new
+// lease and returns it. Running jobs last updated before staleBefore were
+// abandoned by a worker and are claimed again.
+func (db *DB) ClaimRecipeImportJob(staleBefore time.Time) (*models.RecipeImportJob, error) {
+	leaseId, _ := uuid.NewV7()
+	// SKIP LOCKED lets the workers of several replicas claim jobs at once
+	job, err := scanRecipeImportJob(db.QueryRow(`UPDATE recipe_import_jobs SET state = $1, lease_id = $2, updated_at = $3
+		WHERE id = (SELECT id FROM recipe_import_jobs WHERE state = $4 OR (state = $5 AND updated_at < $6) ORDER BY created_at, id LIMIT 1 FOR UPDATE SKIP LOCKED)
+		RETURNING `+recipeImportJobColumns, models.JobRunning, leaseId.String(), time.Now().UTC(), models.JobQueued, models.JobRunning, staleBefore))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("recipe import job %w", proxy.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to claim recipe import job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// RenewRecipeImportJob keeps the job from being claimed again while its worker
+// is still running it. In a Transaction the job's row stays locked until it
+// ends, so it cannot be claimed again before then either.
+func (db *DB) RenewRecipeImportJob(job models.RecipeImportJob) error {
+	result, err := db.Exec("UPDATE recipe_import_jobs SET updated_at = $1 WHERE id = $2 AND state = $3 AND lease_id = $4",
+		time.Now().UTC(), job.Id, models.JobRunning, job.LeaseId)
+	if err != nil {
+		return fmt.Errorf("failed to renew recipe import job: %w", err)
+	}
+
+	return db.checkLease(result, job.Id)
+}
+
+// FinishRecipeImportJob marks the job Done and saves its result, as long as
+// the job is still held by the lease it was claimed with
+func (db *DB) FinishRecipeImportJob(job models.RecipeImportJob) error {
+	result, err := db.Exec("UPDATE recipe_import_jobs SET state = $1, status = $2, ingredients = $3, error = $4, created_item_ids = $5, updated_item_ids = $6, updated_at = $7 WHERE id = $8 AND state = $9 AND lease_id = $10",
+		models.JobDone, job.Status, job.Ingredients, job.Error, encodeJSON(job.CreatedItemIds), encodeJSON(job.UpdatedItemIds), time.Now().UTC(), job.Id, models.JobRunning, job.LeaseId)
+	if err != nil {
+		return fmt.Errorf("failed to finish recipe import job: %w", err)
+	}
+
+	return db.checkLease(result, job.Id)
+}
+
+// checkLease tells why an update of a job under its lease changed nothing
+func (db *DB) checkLease(result sql.Result, id string) error {
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		return nil
+	}
+
+	if _, err := db.GetRecipeImportJob(id); err != nil {
+		return err
+	}
+
+	return proxy.ErrLeaseLost
+}
+
+// Saved Recipe Methods
+
+// CreateSavedRecipe adds the recipe to its household's library, setting its
+// id and timestamps
+func (db *DB) CreateSavedRecipe(recipe models.SavedRecipe) (*models.SavedRecipe, error) {
+	if _, err := db.GetHousehold(recipe.HouseholdId); err != nil {
+		return nil, err
+	}
+
+	uuidv7, _ := uuid.NewV7()
+	recipe.Id = uuidv7.String()
+	recipe.Ingredients = append(make([]models.RecipeIngredient, 0), recipe.Ingredients...)
+	recipe.CreatedAt = time.Now().UTC()
+	recipe.UpdatedAt = recipe.CreatedAt
+
+	_, err := db.Exec("INSERT INTO saved_recipes (id, household_id, title, source_url, servings, ingredients, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		recipe.Id, recipe.HouseholdId, recipe.Title, recipe.SourceUrl, recipe.Servings, encodeJSON(recipe.Ingredients), recipe.CreatedAt, recipe.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create saved recipe: %w", err)
+	}
+
+	return &recipe, nil
+}
+
+func (db *DB) GetSavedRecipe(id string) (*models.SavedRecipe, error) {
+	recipe, err := scanSavedRecipe(db.QueryRow("SELECT "+savedRecipeColumns+" FROM saved_recipes WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("saved recipe %w", proxy.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get saved recipe: %w", err)
+	}
+
+	return &recipe, nil
+}
+
+// ListSavedRecipes returns the household's recipes by title
+func (db *DB) ListSavedRecipes(householdId string) ([]models.SavedRecipe, error) {
+	rows, err := db.Query("SELECT "+savedRecipeColumns+" FROM saved_recipes WHERE household_id = $1 ORDER BY title, id", householdId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved recipes: %w", err)
+	}
+	defer rows.Close()
+
+	recipes := make([]models.SavedRecipe, 0)
+	for rows.Next() {
+		recipe, err := scanSavedRecipe(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved recipe: %w", err)
+		}
+		recipes = append(recipes, recipe)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error in rows iteration: %w", err)
+	}
+
+	return recipes, nil
+}
+
+func (db *DB) UpdateSavedRecipe(id string, patch models.SavedRecipePatch) (*models.SavedRecipe, error) {
+	assignments := []string{"updated_at = $1"}
+	args := []interface{}{time.Now().UTC()}
+	set := func(column string, value interface{}) {
+		args = append(args, value)
+		assignments = append(assignments, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if patch.Title != nil {
+		set("title", *patch.Title)
+	}
+	if patch.Servings != nil {
+		set("servings", *patch.Servings)
+	}
+	if patch.Ingredients != nil {
+		set("ingredients", encodeJSON(*patch.Ingredients))
+	}
+
+	args = append(args, id)
+	query := fmt.Sprintf("UPDATE saved_recipes SET %s WHERE id = $%d", strings.Join(assignments, ", "), len(args))
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update saved recipe: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, fmt.Errorf("saved recipe %w", proxy.ErrNotFound)
+	}
+
+	return db.GetSavedRecipe(id)
+}
+
+func (db *DB) DeleteSavedRecipe(id string) error {
+	result, err := db.Exec("DELETE FROM saved_recipes WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved recipe: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("saved recipe %w", proxy.ErrNotFound)
+	}
+
+	return nil
+}
+
+const groceryItemColumns = "id, name, kind, category, store_override, household_id, list_id, checked, quantity, unit, extra_quantities, sources"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanGroceryItem reads a row selected with groceryItemColumns
+func scanGroceryItem(row rowScanner) (models.GroceryItem, error) {
+	var item models.GroceryItem
+	var category, storeOverride, listId, unit, extraQuantities, sources sql.NullString
+	var checked sql.NullBool
+	var quantity sql.NullFloat64
+	err := row.Scan(&item.Id, &item.Name, &item.Kind, &category, &storeOverride, &item.HouseholdId, &listId, &checked, &quantity, &unit, &extraQuantities, &sources)
+	if err != nil {
+		return item, err
+	}
+
+	item.Category = category.String
+	item.StoreOverride = models.StorePreference(storeOverride.String)
+	item.ListId = listId.String
+	item.Checked = checked.Bool
+	item.Quantity = quantity.Float64
+	item.Unit = unit.String
+	if err := decodeJSON(extraQuantities, &item.ExtraQuantities); err != nil {
+		return item, fmt.Errorf("failed to decode extra quantities: %w", err)
+	}
+	if err := decodeJSON(sources, &item.Sources); err != nil {
+		return item, fmt.Errorf("failed to decode sources: %w", err)
+	}
+
+	return item, nil
+}
+
+const recipeImportJobColumns = "id, household_id, list_id, item_id, url, servings, state, status, ingredients, error, created_item_ids, updated_item_ids, created_at, updated_at, lease_id"
+
+// scanRecipeImportJob reads a row selected with recipeImportJobColumns
+func scanRecipeImportJob(row rowScanner) (models.RecipeImportJob, error) {
+	var job models.RecipeImportJob
+	var itemId, status, jobError, createdItemIds, updatedItemIds, leaseId sql.NullString
+	var servings sql.NullFloat64
+	err := row.Scan(&job.Id, &job.HouseholdId, &job.ListId, &itemId, &job.Url, &servings, &job.State, &status, &job.Ingredients, &jobError, &createdItemIds, &updatedItemIds, &job.CreatedAt, &job.UpdatedAt, &leaseId)
+	if err != nil {
+		return job, err
+	}
+
+	job.ItemId = itemId.String
+	job.LeaseId = leaseId.String
+	job.Servings = servings.Float64
+	job.Status = models.RecipeImportStatus(status.String)
+	job.Error = jobError.String
+	job.CreatedItemIds = make([]string, 0)
+	job.UpdatedItemIds = make([]string, 0)
+	if err := decodeJSON(createdItemIds, &job.CreatedItemIds); err != nil {
+		return job, fmt.Errorf("failed to decode created item ids: %w", err)
+	}
+	if err := decodeJSON(updatedItemIds, &job.UpdatedItemIds); err != nil {
+		return job, fmt.Errorf("failed to decode updated item ids: %w", err)
+	}
+
+	return job, nil
+}
+
+const savedRecipeColumns = "id, household_id, title, source_url, servings, ingredients, created_at, updated_at"
+
+// scanSavedRecipe reads a row selected with savedRecipeColumns
+func scanSavedRecipe(row rowScanner) (models.SavedRecipe, error) {
+	var recipe models.SavedRecipe
+	var sourceUrl, ingredients sql.NullString
+	var servings sql.NullFloat64
+	err := row.Scan(&recipe.Id, &recipe.HouseholdId, &recipe.Title, &sourceUrl, &servings, &ingredients, &recipe.CreatedAt, &recipe.UpdatedAt)
+	if err != nil {
+		return recipe, err
+	}
+
+	recipe.SourceUrl = sourceUrl.String
+	recipe.Servings = servings.Float64
+	recipe.Ingredients = make([]models.RecipeIngredient, 0)
+	if err := decodeJSON(ingredients, &recipe.Ingredients); err != nil {
+		return recipe, fmt.Errorf("failed to decode ingredients: %w", err)
+	}
+
+	return recipe, nil
+}
+
+// encodeJSON stores empty slices as NULL so rows written before a column
+// existed and rows with nothing in it look the same
+func encodeJSON[T any](values []T) interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+
+	encoded, _ := json.Marshal(values)
+	return string(encoded)
+}
+
+func decodeJSON[T any](column sql.NullString, values *[]T) error {
+	if !column.Valid || column.String == "" {
+		return nil
+	}
+
+	return json.Unmarshal([]byte(column.String), values)
+}
+
+// Close closes the database connection
+func (db *DB) Close() error {
+	return db.DB.Close()
+}
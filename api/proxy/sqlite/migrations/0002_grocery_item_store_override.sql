@@ -0,0 +1 @@
+ALTER TABLE grocery_items ADD COLUMN store_override TEXT;
@@ -0,0 +1,2 @@
+ALTER TABLE grocery_items ADD COLUMN quantity REAL;
+ALTER TABLE grocery_items ADD COLUMN unit TEXT;
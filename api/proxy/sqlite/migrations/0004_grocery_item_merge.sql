@@ -0,0 +1,3 @@
+-- JSON encoded []models.Quantity and []string
+ALTER TABLE grocery_items ADD COLUMN extra_quantities TEXT;
+ALTER TABLE grocery_items ADD COLUMN sources TEXT;
@@ -0,0 +1,10 @@
+-- The order of a household's list, as models.LayoutBlock rows. Text blocks are
+-- section headers and GroceryItemId blocks point at grocery_items.
+CREATE TABLE IF NOT EXISTS grocery_layout_blocks (
+    household_id TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    type TEXT NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (household_id, position),
+    FOREIGN KEY (household_id) REFERENCES households(id) ON DELETE CASCADE
+);
@@ -0,0 +1,39 @@
+-- Households can have several lists. Each household's default list shares its
+-- id, so clients that only know the household id keep seeing the same items.
+CREATE TABLE IF NOT EXISTS grocery_lists (
+    id TEXT PRIMARY KEY,
+    household_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    FOREIGN KEY (household_id) REFERENCES households(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_grocery_lists_household_id ON grocery_lists(household_id);
+
+-- Databases from before foreign keys were enforced can hold items and layouts
+-- of households that were deleted. They could not be shown and have no list to
+-- move to.
+DELETE FROM grocery_items WHERE household_id NOT IN (SELECT id FROM households);
+DELETE FROM grocery_layout_blocks WHERE household_id NOT IN (SELECT id FROM households);
+
+INSERT INTO grocery_lists (id, household_id, name)
+SELECT id, id, 'Groceries' FROM households
+WHERE id NOT IN (SELECT id FROM grocery_lists);
+
+ALTER TABLE grocery_items ADD COLUMN list_id TEXT REFERENCES grocery_lists(id) ON DELETE CASCADE;
+UPDATE grocery_items SET list_id = household_id WHERE list_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_grocery_items_list_id ON grocery_items(list_id);
+
+-- Layouts belong to a list now rather than to the household
+CREATE TABLE IF NOT EXISTS grocery_list_layout_blocks (
+    list_id TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    type TEXT NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (list_id, position),
+    FOREIGN KEY (list_id) REFERENCES grocery_lists(id) ON DELETE CASCADE
+);
+
+INSERT INTO grocery_list_layout_blocks (list_id, position, type, value)
+SELECT household_id, position, type, value FROM grocery_layout_blocks;
+
+DROP TABLE grocery_layout_blocks;
@@ -0,0 +1,9 @@
+-- Household-specific aisles that take precedence over data.Categories.
+-- item_name is stored as parsing.ParseItemName returns it.
+CREATE TABLE IF NOT EXISTS category_overrides (
+    household_id TEXT NOT NULL,
+    item_name TEXT NOT NULL,
+    category TEXT NOT NULL,
+    PRIMARY KEY (household_id, item_name),
+    FOREIGN KEY (household_id) REFERENCES households(id) ON DELETE CASCADE
+);
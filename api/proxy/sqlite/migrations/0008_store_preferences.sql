@@ -0,0 +1,19 @@
+-- Category rules send every item of a category to a store, e.g. Meat -> butcher
+CREATE TABLE IF NOT EXISTS store_category_rules (
+    household_id TEXT NOT NULL,
+    category TEXT NOT NULL,
+    store TEXT NOT NULL,
+    PRIMARY KEY (household_id, category),
+    FOREIGN KEY (household_id) REFERENCES households(id) ON DELETE CASCADE
+);
+
+-- How often a household has put an item at a store, keyed by the singular
+-- parsed item name so "Onions" and "onion" share a history
+CREATE TABLE IF NOT EXISTS store_history (
+    household_id TEXT NOT NULL,
+    item_name TEXT NOT NULL,
+    store TEXT NOT NULL,
+    uses INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (household_id, item_name, store),
+    FOREIGN KEY (household_id) REFERENCES households(id) ON DELETE CASCADE
+);
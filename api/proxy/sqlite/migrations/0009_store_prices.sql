@@ -0,0 +1,9 @@
+-- The latest known price of an item at a store. Prices are not household
+-- specific. item_name is stored as prices.NormalizeName returns it.
+CREATE TABLE IF NOT EXISTS store_prices (
+    item_name TEXT NOT NULL,
+    store_name TEXT NOT NULL,
+    price REAL NOT NULL,
+    last_updated TIMESTAMP NOT NULL,
+    PRIMARY KEY (item_name, store_name)
+);
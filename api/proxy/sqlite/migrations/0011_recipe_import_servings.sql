@@ -0,0 +1 @@
+ALTER TABLE recipe_import_jobs ADD COLUMN servings REAL;
@@ -0,0 +1 @@
+ALTER TABLE households ADD COLUMN measurement_system TEXT;
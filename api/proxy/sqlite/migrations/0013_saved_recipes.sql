@@ -0,0 +1,15 @@
+-- A household's recipe library. Ingredients are stored as JSON, each with the
+-- line it was parsed from, so corrections to the parse are kept.
+CREATE TABLE IF NOT EXISTS saved_recipes (
+    id TEXT PRIMARY KEY,
+    household_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    source_url TEXT,
+    servings REAL,
+    ingredients TEXT,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (household_id) REFERENCES households(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_recipes_household ON saved_recipes(household_id, title);
@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"api/proxy"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMigrateBaseline upgrades a database created by the original init.sql,
+// back when foreign keys were not enforced, so it holds an item of a
+// household that was deleted
+func TestMigrateBaseline(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "groceries.db")
+
+	baseline, err := os.ReadFile(filepath.Join("testdata", "baseline.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old.SetMaxOpenConns(1)
+	statements := []string{
+		string(baseline),
+		"PRAGMA foreign_keys = OFF",
+		"INSERT INTO households (id, name) VALUES ('home', 'Home')",
+		"INSERT INTO grocery_items (id, name, kind, household_id) VALUES ('milk', 'Milk', 'grocery', 'home')",
+		"INSERT INTO grocery_items (id, name, kind, household_id) VALUES ('orphan', 'Eggs', 'grocery', 'gone')",
+		"INSERT INTO scheduled_items (task_id, date) VALUES ('orphan', '2024-01-01')",
+	}
+	for _, statement := range statements {
+		if _, err := old.Exec(statement); err != nil {
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+	old.Close()
+
+	db, err := NewDB(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate again: %v", err)
+	}
+
+	milk, err := db.GetGroceryItem("milk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if milk.ListId != "home" {
+		t.Errorf("milk is on list %q, want the default list %q", milk.ListId, "home")
+	}
+
+	if _, err := db.GetGroceryItem("orphan"); !errors.Is(err, proxy.ErrNotFound) {
+		t.Errorf("orphaned item: got %v, want ErrNotFound", err)
+	}
+
+	var scheduled int
+	if err := db.QueryRow("SELECT COUNT(*) FROM scheduled_items").Scan(&scheduled); err != nil {
+		t.Fatal(err)
+	}
+	if scheduled != 0 {
+		t.Errorf("%d scheduled items left for the orphaned item", scheduled)
+	}
+}
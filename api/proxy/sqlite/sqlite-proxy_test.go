@@ -0,0 +1,24 @@
+package sqlite
+
+import (
+	"api/proxy"
+	"api/proxy/storetest"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) proxy.Store {
+		db, err := NewDB(filepath.Join(t.TempDir(), "groceries.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		if err := db.Migrate(); err != nil {
+			t.Fatal(err)
+		}
+
+		return db
+	})
+}
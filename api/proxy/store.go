@@ -0,0 +1,111 @@
+package proxy
+
+import (
+	"api/models"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is wrapped by every Store method that is given an id that does
+// not exist, e.g. "grocery item not found"
+var ErrNotFound = errors.New("not found")
+
+// ErrLeaseLost is returned when a recipe import job is renewed or finished
+// with a lease that is no longer its latest, e.g. because the lease ran out
+// and another worker claimed the job
+var ErrLeaseLost = errors.New("recipe import job lease lost")
+
+// Store is the persistence layer used by the providers. proxy/sqlite is the
+// default implementation, proxy/postgres the one for several API replicas
+// sharing a database, and proxy/memory an in-process one for tests. All three
+// run the proxy/storetest suite.
+type Store interface {
+	// Transaction runs fn with a Store whose changes are all committed when fn
+	// returns nil and all rolled back when it returns an error. Called on the
+	// Store given to fn, it runs in the same transaction.
+	Transaction(fn func(tx Store) error) error
+
+	// Households
+	CreateHousehold(name string) (*models.Household, error)
+	CreateUserHousehold(id string) (*models.Household, error)
+	GetHousehold(id string) (*models.Household, error)
+	UpdateHousehold(id, name string) error
+	SetHouseholdMeasurementSystem(id string, system models.MeasurementSystem) error
+	DeleteHousehold(id string) error
+	ListHouseholds() ([]models.Household, error)
+
+	// Users
+	CreateUser(name string) (*models.User, error)
+	GetUser(id string) (*models.User, error)
+	UpdateUser(id string, name string) error
+	DeleteUser(id string) error
+	ListUsers() ([]models.User, error)
+
+	// Household users
+	AddUserToHousehold(userId, householdId string) error
+	RemoveUserFromHousehold(userId, householdId string) error
+	GetHouseholdUsers(householdId string) ([]models.User, error)
+	GetUserHouseholds(userId string) ([]models.Household, error)
+
+	// Grocery lists
+	CreateGroceryList(list models.GroceryList) (*models.GroceryList, error)
+	GetGroceryList(id string) (*models.GroceryList, error)
+	ListGroceryLists(householdId string) ([]models.GroceryList, error)
+	RenameGroceryList(id, name string) error
+	DeleteGroceryList(id string) error
+	// LockGroceryList holds the list until the transaction it is called in
+	// ends, so transactions that read its items before changing them take
+	// turns
+	LockGroceryList(id string) error
+
+	// Grocery items
+	CreateGroceryItem(item models.GroceryItem) (*models.GroceryItem, error)
+	GetGroceryItem(id string) (*models.GroceryItem, error)
+	UpdateGroceryItemStatus(id string, checked bool) error
+	UpdateGroceryItem(id string, patch models.GroceryItemPatch) (*models.GroceryItem, error)
+	DeleteGroceryItems(ids []string) error
+	ListGroceryItemsByHousehold(householdId string) ([]models.GroceryItem, error)
+	ListGroceryItemsByList(listId string) ([]models.GroceryItem, error)
+
+	// Layouts
+	GetLayout(listId string) ([]models.LayoutBlock, error)
+	SaveLayout(listId string, layout []models.LayoutBlock) error
+
+	// Category overrides
+	ListCategoryOverrides(householdId string) ([]models.CategoryOverride, error)
+	SaveCategoryOverride(override models.CategoryOverride) error
+	DeleteCategoryOverride(householdId, itemName string) error
+
+	// Store preferences
+	ListStoreRules(householdId string) ([]models.StoreRule, error)
+	SaveStoreRule(rule models.StoreRule) error
+	DeleteStoreRule(householdId, category string) error
+	ListStoreHistory(householdId string) ([]models.StoreChoice, error)
+	RecordStoreChoice(householdId, itemName string, store models.StorePreference) error
+
+	// Prices
+	SaveStorePrices(storePrices []models.StoreData) error
+	ListStorePrices() ([]models.StoreData, error)
+	SavePriceChecks(checks []models.PriceCheck) error
+	ListPriceChecks() ([]models.PriceCheck, error)
+
+	// Recipe import jobs
+	CreateRecipeImportJob(job models.RecipeImportJob) (*models.RecipeImportJob, error)
+	GetRecipeImportJob(id string) (*models.RecipeImportJob, error)
+	ClaimRecipeImportJob(staleBefore time.Time) (*models.RecipeImportJob, error)
+	RenewRecipeImportJob(job models.RecipeImportJob) error
+	FinishRecipeImportJob(job models.RecipeImportJob) error
+
+	// Saved recipes
+	CreateSavedRecipe(recipe models.SavedRecipe) (*models.SavedRecipe, error)
+	GetSavedRecipe(id string) (*models.SavedRecipe, error)
+	ListSavedRecipes(householdId string) ([]models.SavedRecipe, error)
+	UpdateSavedRecipe(id string, patch models.SavedRecipePatch) (*models.SavedRecipe, error)
+	DeleteSavedRecipe(id string) error
+
+	// Task schedules
+	GetTaskSchedule(taskIds []string) ([]models.TaskScheduleItem, error)
+	CreateTaskSchedule(taskId string, dates []string) error
+
+	Close() error
+}
@@ -0,0 +1,957 @@
+// Package storetest is the behaviour every proxy.Store has to share. Each
+// driver runs it from its own tests, so the memory store the provider tests
+// use keeps doing what sqlite and postgres do.
+package storetest
+
+import (
+	"api/models"
+	"api/proxy"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Run runs the suite, calling newStore for an empty store in every subtest
+func Run(t *testing.T, newStore func(t *testing.T) proxy.Store) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, store proxy.Store)
+	}{
+		{"Households", testHouseholds},
+		{"Users", testUsers},
+		{"GroceryLists", testGroceryLists},
+		{"GroceryItems", testGroceryItems},
+		{"Layouts", testLayouts},
+		{"CategoryOverrides", testCategoryOverrides},
+		{"StorePreferences", testStorePreferences},
+		{"StorePrices", testStorePrices},
+		{"PriceChecks", testPriceChecks},
+		{"Transactions", testTransactions},
+		{"RecipeImportJobs", testRecipeImportJobs},
+		{"SavedRecipes", testSavedRecipes},
+		{"TaskSchedules", testTaskSchedules},
+		{"DeleteHousehold", testDeleteHousehold},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStore(t))
+		})
+	}
+}
+
+func createHousehold(t *testing.T, store proxy.Store, name string) *models.Household {
+	t.Helper()
+
+	household, err := store.CreateHousehold(name)
+	if err != nil {
+		t.Fatalf("CreateHousehold: %v", err)
+	}
+
+	return household
+}
+
+func createGroceryItem(t *testing.T, store proxy.Store, item models.GroceryItem) *models.GroceryItem {
+	t.Helper()
+
+	if item.Kind == "" {
+		item.Kind = models.GroceryKind
+	}
+	created, err := store.CreateGroceryItem(item)
+	if err != nil {
+		t.Fatalf("CreateGroceryItem %s: %v", item.Name, err)
+	}
+
+	return created
+}
+
+func expectNotFound(t *testing.T, what string, err error) {
+	t.Helper()
+
+	if !errors.Is(err, proxy.ErrNotFound) {
+		t.Errorf("%s: got %v, want ErrNotFound", what, err)
+	}
+}
+
+func itemNames(items []models.GroceryItem) []string {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+	}
+	return names
+}
+
+func expectStrings(t *testing.T, what string, got []string, want ...string) {
+	t.Helper()
+
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s: got %q, want %q", what, got, want)
+	}
+}
+
+func testHouseholds(t *testing.T, store proxy.Store) {
+	household := createHousehold(t, store, "Home")
+	if household.Id == "" {
+		t.Fatal("CreateHousehold did not set an id")
+	}
+
+	if err := store.UpdateHousehold(household.Id, "Flat"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetHouseholdMeasurementSystem(household.Id, models.MetricSystem); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.GetHousehold(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.Household{Id: household.Id, Name: "Flat", MeasurementSystem: models.MetricSystem}
+	if *got != want {
+		t.Errorf("GetHousehold: got %+v, want %+v", *got, want)
+	}
+
+	userHousehold, err := store.CreateUserHousehold("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if userHousehold.Id != "user-1" {
+		t.Errorf("CreateUserHousehold: got id %q, want %q", userHousehold.Id, "user-1")
+	}
+	if _, err := store.CreateUserHousehold("user-1"); err == nil {
+		t.Error("CreateUserHousehold twice: want an error")
+	}
+
+	households, err := store.ListHouseholds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, len(households))
+	for i, household := range households {
+		names[i] = household.Name
+	}
+	expectStrings(t, "ListHouseholds", names, "Flat", "user-1")
+
+	_, err = store.GetHousehold("missing")
+	expectNotFound(t, "GetHousehold", err)
+	expectNotFound(t, "UpdateHousehold", store.UpdateHousehold("missing", "x"))
+	expectNotFound(t, "SetHouseholdMeasurementSystem", store.SetHouseholdMeasurementSystem("missing", models.MetricSystem))
+	expectNotFound(t, "DeleteHousehold", store.DeleteHousehold("missing"))
+}
+
+func testUsers(t *testing.T, store proxy.Store) {
+	household := createHousehold(t, store, "Home")
+
+	bo, err := store.CreateUser("Bo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	al, err := store.CreateUser("Al")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.UpdateUser(bo.Id, "Bea"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.GetUser(bo.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "Bea" {
+		t.Errorf("GetUser after UpdateUser: got %q, want %q", got.Name, "Bea")
+	}
+
+	for _, user := range []*models.User{bo, al} {
+		if err := store.AddUserToHousehold(user.Id, household.Id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.AddUserToHousehold(al.Id, household.Id); err == nil {
+		t.Error("AddUserToHousehold twice: want an error")
+	}
+	expectNotFound(t, "AddUserToHousehold unknown household", store.AddUserToHousehold(al.Id, "missing"))
+
+	members, err := store.GetHouseholdUsers(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, len(members))
+	for i, member := range members {
+		names[i] = member.Name
+	}
+	expectStrings(t, "GetHouseholdUsers", names, "Al", "Bea")
+
+	households, err := store.GetUserHouseholds(al.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(households) != 1 || households[0].Id != household.Id {
+		t.Errorf("GetUserHouseholds: got %+v", households)
+	}
+
+	if err := store.RemoveUserFromHousehold(al.Id, household.Id); err != nil {
+		t.Fatal(err)
+	}
+	expectNotFound(t, "RemoveUserFromHousehold twice", store.RemoveUserFromHousehold(al.Id, household.Id))
+
+	if err := store.DeleteUser(bo.Id); err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.GetUser(bo.Id)
+	expectNotFound(t, "GetUser after DeleteUser", err)
+	expectNotFound(t, "DeleteUser twice", store.DeleteUser(bo.Id))
+
+	members, err = store.GetHouseholdUsers(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 0 {
+		t.Errorf("GetHouseholdUsers after removing everyone: got %+v", members)
+	}
+
+	users, err := store.ListUsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].Name != "Al" {
+		t.Errorf("ListUsers: got %+v", users)
+	}
+}
+
+func testGroceryLists(t *testing.T, store proxy.Store) {
+	household := createHousehold(t, store, "Home")
+
+	defaultList, err := store.GetGroceryList(household.Id)
+	if err != nil {
+		t.Fatalf("default list: %v", err)
+	}
+	if defaultList.HouseholdId != household.Id || defaultList.Name != models.DefaultGroceryListName {
+		t.Errorf("default list: got %+v", *defaultList)
+	}
+
+	party, err := store.CreateGroceryList(models.GroceryList{HouseholdId: household.Id, Name: "Party"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if party.Id == "" {
+		t.Fatal("CreateGroceryList did not set an id")
+	}
+	if _, err := store.CreateGroceryList(models.GroceryList{Id: "camping", HouseholdId: household.Id, Name: "Camping"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.CreateGroceryList(models.GroceryList{HouseholdId: "missing", Name: "Lost"})
+	expectNotFound(t, "CreateGroceryList unknown household", err)
+
+	if err := store.RenameGroceryList(party.Id, "Barbecue"); err != nil {
+		t.Fatal(err)
+	}
+	expectNotFound(t, "RenameGroceryList", store.RenameGroceryList("missing", "x"))
+
+	lists, err := store.ListGroceryLists(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, len(lists))
+	for i, list := range lists {
+		names[i] = list.Name
+	}
+	expectStrings(t, "ListGroceryLists", names, models.DefaultGroceryListName, "Barbecue", "Camping")
+
+	createGroceryItem(t, store, models.GroceryItem{HouseholdId: household.Id, ListId: party.Id, Name: "Sausages"})
+	if err := store.SaveLayout(party.Id, []models.LayoutBlock{{Type: models.Text, Value: "Butcher"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.DeleteGroceryList(party.Id); err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.GetGroceryList(party.Id)
+	expectNotFound(t, "GetGroceryList after DeleteGroceryList", err)
+	expectNotFound(t, "DeleteGroceryList twice", store.DeleteGroceryList(party.Id))
+
+	items, err := store.ListGroceryItemsByHousehold(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectStrings(t, "items after DeleteGroceryList", itemNames(items))
+}
+
+func testGroceryItems(t *testing.T, store proxy.Store) {
+	household := createHousehold(t, store, "Home")
+	other, err := store.CreateGroceryList(models.GroceryList{HouseholdId: household.Id, Name: "Party"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	milk := createGroceryItem(t, store, models.GroceryItem{HouseholdId: household.Id, Name: "Milk", Category: "Dairy"})
+	if milk.Id == "" {
+		t.Fatal("CreateGroceryItem did not set an id")
+	}
+	if milk.ListId != household.Id {
+		t.Errorf("item without a list: got list %q, want the default list %q", milk.ListId, household.Id)
+	}
+	createGroceryItem(t, store, models.GroceryItem{Id: "apples", HouseholdId: household.Id, Name: "Apples"})
+	createGroceryItem(t, store, models.GroceryItem{HouseholdId: household.Id, ListId: other.Id, Name: "Chips"})
+
+	_, err = store.CreateGroceryItem(models.GroceryItem{HouseholdId: household.Id, ListId: "missing", Name: "Lost", Kind: models.GroceryKind})
+	expectNotFound(t, "CreateGroceryItem unknown list", err)
+	_, err = store.CreateGroceryItem(models.GroceryItem{HouseholdId: "missing", Name: "Lost", Kind: models.GroceryKind})
+	expectNotFound(t, "CreateGroceryItem unknown household", err)
+
+	apples, err := store.GetGroceryItem("apples")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if apples.Name != "Apples" || apples.HouseholdId != household.Id {
+		t.Errorf("GetGroceryItem: got %+v", *apples)
+	}
+	_, err = store.GetGroceryItem("missing")
+	expectNotFound(t, "GetGroceryItem", err)
+
+	if err := store.UpdateGroceryItemStatus(milk.Id, true); err != nil {
+		t.Fatal(err)
+	}
+	expectNotFound(t, "UpdateGroceryItemStatus", store.UpdateGroceryItemStatus("missing", true))
+
+	name := "Oat milk"
+	quantity := 2.5
+	unit := "liter"
+	extra := []models.Quantity{{Amount: 1, Unit: "carton"}}
+	sources := []string{"https://example.com/porridge"}
+	storeOverride := models.StorePreference("Aldi")
+	updated, err := store.UpdateGroceryItem(milk.Id, models.GroceryItemPatch{
+		Name:            &name,
+		Quantity:        &quantity,
+		Unit:            &unit,
+		ExtraQuantities: &extra,
+		Sources:         &sources,
+		StoreOverride:   &storeOverride,
+		ListId:          &other.Id,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.GetGroceryItem(milk.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.GroceryItem{
+		HouseholdId:     household.Id,
+		ListId:          other.Id,
+		Id:              milk.Id,
+		Name:            name,
+		Kind:            models.GroceryKind,
+		StoreOverride:   storeOverride,
+		Category:        "Dairy",
+		Checked:         true,
+		Quantity:        quantity,
+		Unit:            unit,
+		ExtraQuantities: extra,
+		Sources:         sources,
+	}
+	if !reflect.DeepEqual(*updated, want) {
+		t.Errorf("UpdateGroceryItem: got %+v, want %+v", *updated, want)
+	}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("GetGroceryItem after UpdateGroceryItem: got %+v, want %+v", *got, want)
+	}
+
+	_, err = store.UpdateGroceryItem("missing", models.GroceryItemPatch{Name: &name})
+	expectNotFound(t, "UpdateGroceryItem", err)
+	missingList := "missing"
+	_, err = store.UpdateGroceryItem(milk.Id, models.GroceryItemPatch{ListId: &missingList})
+	expectNotFound(t, "UpdateGroceryItem to an unknown list", err)
+
+	items, err := store.ListGroceryItemsByHousehold(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectStrings(t, "ListGroceryItemsByHousehold", itemNames(items), "Apples", "Chips", "Oat milk")
+
+	items, err = store.ListGroceryItemsByList(other.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectStrings(t, "ListGroceryItemsByList", itemNames(items), "Chips", "Oat milk")
+
+	_, err = store.ListGroceryItemsByList("missing")
+	expectNotFound(t, "ListGroceryItemsByList", err)
+	_, err = store.ListGroceryItemsByHousehold("missing")
+	expectNotFound(t, "ListGroceryItemsByHousehold", err)
+
+	if err := store.DeleteGroceryItems([]string{milk.Id, "apples"}); err != nil {
+		t.Fatal(err)
+	}
+	expectNotFound(t, "DeleteGroceryItems of deleted items", store.DeleteGroceryItems([]string{milk.Id}))
+	if err := store.DeleteGroceryItems(nil); err == nil {
+		t.Error("DeleteGroceryItems without ids: want an error")
+	}
+
+	items, err = store.ListGroceryItemsByHousehold(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectStrings(t, "items after DeleteGroceryItems", itemNames(items), "Chips")
+}
+
+func testLayouts(t *testing.T, store proxy.Store) {
+	household := createHousehold(t, store, "Home")
+
+	layout, err := store.GetLayout(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layout) != 0 {
+		t.Errorf("GetLayout before SaveLayout: got %+v", layout)
+	}
+
+	want := []models.LayoutBlock{
+		{Type: models.Text, Value: "Aldi"},
+		{Type: models.GroceryItemId, Value: "milk"},
+		{Type: models.Text, Value: "Coles"},
+		{Type: models.GroceryItemId, Value: "bread"},
+	}
+	if err := store.SaveLayout(household.Id, want); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveLayout(household.Id, want[:2]); err != nil {
+		t.Fatal(err)
+	}
+
+	layout, err = store.GetLayout(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(layout, want[:2]) {
+		t.Errorf("GetLayout: got %+v, want %+v", layout, want[:2])
+	}
+
+	expectNotFound(t, "SaveLayout", store.SaveLayout("missing", want))
+}
+
+func testCategoryOverrides(t *testing.T, store proxy.Store) {
+	household := createHousehold(t, store, "Home")
+
+	for _, override := range []models.CategoryOverride{
+		{HouseholdId: household.Id, ItemName: "tofu", Category: "Dairy"},
+		{HouseholdId: household.Id, ItemName: "basil", Category: "Produce"},
+		{HouseholdId: household.Id, ItemName: "tofu", Category: "Meat"},
+	} {
+		if err := store.SaveCategoryOverride(override); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectNotFound(t, "SaveCategoryOverride unknown household",
+		store.SaveCategoryOverride(models.CategoryOverride{HouseholdId: "missing", ItemName: "tofu", Category: "Meat"}))
+
+	overrides, err := store.ListCategoryOverrides(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.CategoryOverride{
+		{HouseholdId: household.Id, ItemName: "basil", Category: "Produce"},
+		{HouseholdId: household.Id, ItemName: "tofu", Category: "Meat"},
+	}
+	if !reflect.DeepEqual(overrides, want) {
+		t.Errorf("ListCategoryOverrides: got %+v, want %+v", overrides, want)
+	}
+
+	if err := store.DeleteCategoryOverride(household.Id, "basil"); err != nil {
+		t.Fatal(err)
+	}
+	expectNotFound(t, "DeleteCategoryOverride twice", store.DeleteCategoryOverride(household.Id, "basil"))
+}
+
+func testStorePreferences(t *testing.T, store proxy.Store) {
+	household := createHousehold(t, store, "Home")
+
+	for _, rule := range []models.StoreRule{
+		{HouseholdId: household.Id, Category: "Produce", Store: "Market"},
+		{HouseholdId: household.Id, Category: "Dairy", Store: "Aldi"},
+		{HouseholdId: household.Id, Category: "Produce", Store: "Coles"},
+	} {
+		if err := store.SaveStoreRule(rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectNotFound(t, "SaveStoreRule unknown household",
+		store.SaveStoreRule(models.StoreRule{HouseholdId: "missing", Category: "Dairy", Store: "Aldi"}))
+
+	rules, err := store.ListStoreRules(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRules := []models.StoreRule{
+		{HouseholdId: household.Id, Category: "Dairy", Store: "Aldi"},
+		{HouseholdId: household.Id, Category: "Produce", Store: "Coles"},
+	}
+	if !reflect.DeepEqual(rules, wantRules) {
+		t.Errorf("ListStoreRules: got %+v, want %+v", rules, wantRules)
+	}
+
+	if err := store.DeleteStoreRule(household.Id, "Dairy"); err != nil {
+		t.Fatal(err)
+	}
+	expectNotFound(t, "DeleteStoreRule twice", store.DeleteStoreRule(household.Id, "Dairy"))
+
+	for _, choice := range []struct {
+		item  string
+		store models.StorePreference
+	}{
+		{"milk", "Coles"},
+		{"bread", "Aldi"},
+		{"milk", "Aldi"},
+		{"milk", "Aldi"},
+	} {
+		if err := store.RecordStoreChoice(household.Id, choice.item, choice.store); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectNotFound(t, "RecordStoreChoice unknown household", store.RecordStoreChoice("missing", "milk", "Aldi"))
+
+	choices, err := store.ListStoreHistory(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantChoices := []models.StoreChoice{
+		{HouseholdId: household.Id, ItemName: "milk", Store: "Aldi", Uses: 2},
+		{HouseholdId: household.Id, ItemName: "bread", Store: "Aldi", Uses: 1},
+		{HouseholdId: household.Id, ItemName: "milk", Store: "Coles", Uses: 1},
+	}
+	if !reflect.DeepEqual(choices, wantChoices) {
+		t.Errorf("ListStoreHistory: got %+v, want %+v", choices, wantChoices)
+	}
+}
+
+func testStorePrices(t *testing.T, store proxy.Store) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	err := store.SaveStorePrices([]models.StoreData{
+		{ItemName: "milk", StoreName: "Coles", Price: 1.60, LastUpdated: now},
+		{ItemName: "bread", StoreName: "Aldi", Price: 2.49, LastUpdated: now},
+		{ItemName: "milk", StoreName: "Aldi", Price: 1.50, LastUpdated: now},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an older price does not replace a newer one, a newer one does
+	err = store.SaveStorePrices([]models.StoreData{
+		{ItemName: "milk", StoreName: "Coles", Price: 9.99, LastUpdated: now.Add(-time.Hour)},
+		{ItemName: "milk", StoreName: "Aldi", Price: 1.45, LastUpdated: now.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storePrices, err := store.ListStorePrices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.StoreData{
+		{ItemName: "bread", StoreName: "Aldi", Price: 2.49, LastUpdated: now},
+		{ItemName: "milk", StoreName: "Aldi", Price: 1.45, LastUpdated: now.Add(time.Hour)},
+		{ItemName: "milk", StoreName: "Coles", Price: 1.60, LastUpdated: now},
+	}
+	if len(storePrices) != len(want) {
+		t.Fatalf("ListStorePrices: got %+v, want %+v", storePrices, want)
+	}
+	for i := range want {
+		got := storePrices[i]
+		if got.ItemName != want[i].ItemName || got.StoreName != want[i].StoreName || got.Price != want[i].Price || !got.LastUpdated.Equal(want[i].LastUpdated) {
+			t.Errorf("ListStorePrices[%d]: got %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func testPriceChecks(t *testing.T, store proxy.Store) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	err := store.SavePriceChecks([]models.PriceCheck{
+		{ItemName: "milk", StoreName: "Coles", CheckedAt: now.Add(-time.Hour), Found: true},
+		{ItemName: "bread", StoreName: "Coles", CheckedAt: now, Found: false},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.SavePriceChecks([]models.PriceCheck{{ItemName: "milk", StoreName: "Coles", CheckedAt: now, Found: false}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checks, err := store.ListPriceChecks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.PriceCheck{
+		{ItemName: "bread", StoreName: "Coles", CheckedAt: now, Found: false},
+		{ItemName: "milk", StoreName: "Coles", CheckedAt: now, Found: false},
+	}
+	if len(checks) != len(want) {
+		t.Fatalf("ListPriceChecks: got %+v, want %+v", checks, want)
+	}
+	for i := range want {
+		got := checks[i]
+		if got.ItemName != want[i].ItemName || got.StoreName != want[i].StoreName || got.Found != want[i].Found || !got.CheckedAt.Equal(want[i].CheckedAt) {
+			t.Errorf("ListPriceChecks[%d]: got %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func testTransactions(t *testing.T, store proxy.Store) {
+	household := createHousehold(t, store, "Home")
+
+	errRollback := errors.New("roll back")
+	err := store.Transaction(func(tx proxy.Store) error {
+		createGroceryItem(t, tx, models.GroceryItem{HouseholdId: household.Id, Name: "Milk"})
+		// a nested transaction is part of the one it is in
+		return tx.Transaction(func(tx proxy.Store) error {
+			if err := tx.SetHouseholdMeasurementSystem(household.Id, models.MetricSystem); err != nil {
+				return err
+			}
+			return errRollback
+		})
+	})
+	if !errors.Is(err, errRollback) {
+		t.Errorf("Transaction: got %v, want the error of fn", err)
+	}
+	items, err := store.ListGroceryItemsByList(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 0 {
+		t.Errorf("rolled back Transaction left %v", itemNames(items))
+	}
+	if got, err := store.GetHousehold(household.Id); err != nil || got.MeasurementSystem != "" {
+		t.Errorf("rolled back Transaction left household %+v, %v", got, err)
+	}
+
+	err = store.Transaction(func(tx proxy.Store) error {
+		createGroceryItem(t, tx, models.GroceryItem{HouseholdId: household.Id, Name: "Bread"})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err = store.ListGroceryItemsByList(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectStrings(t, "committed Transaction", itemNames(items), "Bread")
+
+	err = store.Transaction(func(tx proxy.Store) error {
+		return tx.LockGroceryList("missing")
+	})
+	expectNotFound(t, "LockGroceryList", err)
+
+	// Transactions that lock the list see each other's items, so only the
+	// first to run adds eggs
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := store.Transaction(func(tx proxy.Store) error {
+				if err := tx.LockGroceryList(household.Id); err != nil {
+					return err
+				}
+				items, err := tx.ListGroceryItemsByList(household.Id)
+				if err != nil {
+					return err
+				}
+				for _, item := range items {
+					if item.Name == "Eggs" {
+						return nil
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				_, err = tx.CreateGroceryItem(models.GroceryItem{HouseholdId: household.Id, ListId: household.Id, Name: "Eggs", Kind: models.GroceryKind})
+				return err
+			})
+			if err != nil {
+				t.Errorf("concurrent Transaction: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	items, err = store.ListGroceryItemsByList(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectStrings(t, "concurrent Transactions", itemNames(items), "Bread", "Eggs")
+}
+
+func testRecipeImportJobs(t *testing.T, store proxy.Store) {
+	household := createHousehold(t, store, "Home")
+
+	_, err := store.ClaimRecipeImportJob(time.Now())
+	expectNotFound(t, "ClaimRecipeImportJob with nothing queued", err)
+
+	first, err := store.CreateRecipeImportJob(models.RecipeImportJob{HouseholdId: household.Id, ListId: household.Id, Url: "https://example.com/soup", Servings: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Id == "" || first.State != models.JobQueued || first.CreatedAt.IsZero() {
+		t.Errorf("CreateRecipeImportJob: got %+v", *first)
+	}
+	_, err = store.CreateRecipeImportJob(models.RecipeImportJob{HouseholdId: household.Id, ListId: "missing", Url: "https://example.com/soup"})
+	expectNotFound(t, "CreateRecipeImportJob unknown list", err)
+
+	// created_at is kept to the second by sqlite, so the second job has to be
+	// created later for the order to be certain
+	time.Sleep(1100 * time.Millisecond)
+	second, err := store.CreateRecipeImportJob(models.RecipeImportJob{HouseholdId: household.Id, ListId: household.Id, Url: "https://example.com/bread"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	abandoned, err := store.ClaimRecipeImportJob(time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if abandoned.Id != first.Id || abandoned.State != models.JobRunning || abandoned.Servings != 4 || abandoned.LeaseId == "" {
+		t.Errorf("ClaimRecipeImportJob: got %+v, want the oldest job running under a lease", *abandoned)
+	}
+	if err := store.RenewRecipeImportJob(*abandoned); err != nil {
+		t.Errorf("RenewRecipeImportJob: %v", err)
+	}
+
+	claimed, err := store.ClaimRecipeImportJob(time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claimed.Id != second.Id {
+		t.Errorf("second ClaimRecipeImportJob: got %s, want %s", claimed.Id, second.Id)
+	}
+
+	_, err = store.ClaimRecipeImportJob(time.Now().Add(-time.Minute))
+	expectNotFound(t, "ClaimRecipeImportJob with every job running", err)
+
+	// a running job that has not been touched since staleBefore is claimed again
+	reclaimed, err := store.ClaimRecipeImportJob(time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reclaimed.Id != first.Id || reclaimed.LeaseId == abandoned.LeaseId {
+		t.Errorf("ClaimRecipeImportJob of a stale job: got %s under lease %s, want %s under a new lease", reclaimed.Id, reclaimed.LeaseId, first.Id)
+	}
+
+	// the worker that abandoned the job can no longer renew or finish it
+	if err := store.RenewRecipeImportJob(*abandoned); !errors.Is(err, proxy.ErrLeaseLost) {
+		t.Errorf("RenewRecipeImportJob with a lost lease: got %v, want ErrLeaseLost", err)
+	}
+	abandoned.Status = models.RecipeFetchFailed
+	if err := store.FinishRecipeImportJob(*abandoned); !errors.Is(err, proxy.ErrLeaseLost) {
+		t.Errorf("FinishRecipeImportJob with a lost lease: got %v, want ErrLeaseLost", err)
+	}
+
+	reclaimed.Status = models.RecipeImported
+	reclaimed.Ingredients = 3
+	reclaimed.CreatedItemIds = []string{"a", "b"}
+	reclaimed.UpdatedItemIds = []string{"c"}
+	if err := store.FinishRecipeImportJob(*reclaimed); err != nil {
+		t.Fatal(err)
+	}
+	expectNotFound(t, "FinishRecipeImportJob", store.FinishRecipeImportJob(models.RecipeImportJob{Id: "missing"}))
+	expectNotFound(t, "RenewRecipeImportJob", store.RenewRecipeImportJob(models.RecipeImportJob{Id: "missing"}))
+	if err := store.FinishRecipeImportJob(*reclaimed); !errors.Is(err, proxy.ErrLeaseLost) {
+		t.Errorf("FinishRecipeImportJob of a Done job: got %v, want ErrLeaseLost", err)
+	}
+
+	done, err := store.GetRecipeImportJob(first.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.State != models.JobDone || done.Status != reclaimed.Status || done.Ingredients != 3 ||
+		!reflect.DeepEqual(done.CreatedItemIds, reclaimed.CreatedItemIds) || !reflect.DeepEqual(done.UpdatedItemIds, reclaimed.UpdatedItemIds) {
+		t.Errorf("GetRecipeImportJob after FinishRecipeImportJob: got %+v", *done)
+	}
+
+	_, err = store.GetRecipeImportJob("missing")
+	expectNotFound(t, "GetRecipeImportJob", err)
+}
+
+func testSavedRecipes(t *testing.T, store proxy.Store) {
+	household := createHousehold(t, store, "Home")
+
+	ingredients := []models.RecipeIngredient{
+		{Line: "2 cups rice", Name: "rice", Quantity: 2, Unit: "cup"},
+		{Line: "1 onion", Name: "onion", Quantity: 1},
+	}
+	curry, err := store.CreateSavedRecipe(models.SavedRecipe{HouseholdId: household.Id, Title: "Curry", SourceUrl: "https://example.com/curry", Servings: 4, Ingredients: ingredients})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if curry.Id == "" || curry.CreatedAt.IsZero() {
+		t.Errorf("CreateSavedRecipe: got %+v", *curry)
+	}
+	if _, err := store.CreateSavedRecipe(models.SavedRecipe{HouseholdId: household.Id, Title: "Bread", Ingredients: ingredients[:1]}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.CreateSavedRecipe(models.SavedRecipe{HouseholdId: "missing", Title: "Lost", Ingredients: ingredients})
+	expectNotFound(t, "CreateSavedRecipe unknown household", err)
+
+	got, err := store.GetSavedRecipe(curry.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Curry" || got.SourceUrl != curry.SourceUrl || got.Servings != 4 || !reflect.DeepEqual(got.Ingredients, ingredients) {
+		t.Errorf("GetSavedRecipe: got %+v", *got)
+	}
+
+	title := "Green curry"
+	corrected := []models.RecipeIngredient{{Line: "2 cups jasmine rice", Name: "jasmine rice", Quantity: 2, Unit: "cup"}}
+	updated, err := store.UpdateSavedRecipe(curry.Id, models.SavedRecipePatch{Title: &title, Ingredients: &corrected})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Title != title || updated.Servings != 4 || !reflect.DeepEqual(updated.Ingredients, corrected) {
+		t.Errorf("UpdateSavedRecipe: got %+v", *updated)
+	}
+	_, err = store.UpdateSavedRecipe("missing", models.SavedRecipePatch{Title: &title})
+	expectNotFound(t, "UpdateSavedRecipe", err)
+
+	recipes, err := store.ListSavedRecipes(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles := make([]string, len(recipes))
+	for i, recipe := range recipes {
+		titles[i] = recipe.Title
+	}
+	expectStrings(t, "ListSavedRecipes", titles, "Bread", "Green curry")
+
+	if err := store.DeleteSavedRecipe(curry.Id); err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.GetSavedRecipe(curry.Id)
+	expectNotFound(t, "GetSavedRecipe after DeleteSavedRecipe", err)
+	expectNotFound(t, "DeleteSavedRecipe twice", store.DeleteSavedRecipe(curry.Id))
+}
+
+func testTaskSchedules(t *testing.T, store proxy.Store) {
+	household := createHousehold(t, store, "Home")
+	task := createGroceryItem(t, store, models.GroceryItem{HouseholdId: household.Id, Name: "Water plants", Kind: models.TaskKind})
+
+	if err := store.CreateTaskSchedule(task.Id, []string{"2024-01-01", "2024-01-08"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CreateTaskSchedule("missing", []string{"2024-01-01"}); err == nil {
+		t.Error("CreateTaskSchedule unknown task: want an error")
+	}
+
+	schedule, err := store.GetTaskSchedule([]string{task.Id, "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.TaskScheduleItem{{TaskId: task.Id, Date: "2024-01-01"}, {TaskId: task.Id, Date: "2024-01-08"}}
+	if !reflect.DeepEqual(schedule, want) {
+		t.Errorf("GetTaskSchedule: got %+v, want %+v", schedule, want)
+	}
+
+	if err := store.DeleteGroceryItems([]string{task.Id}); err != nil {
+		t.Fatal(err)
+	}
+	schedule, err = store.GetTaskSchedule([]string{task.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule) != 0 {
+		t.Errorf("GetTaskSchedule after deleting the task: got %+v", schedule)
+	}
+}
+
+// testDeleteHousehold checks that everything belonging to a household goes
+// with it
+func testDeleteHousehold(t *testing.T, store proxy.Store) {
+	household := createHousehold(t, store, "Home")
+	kept := createHousehold(t, store, "Neighbours")
+
+	user, err := store.CreateUser("Al")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddUserToHousehold(user.Id, household.Id); err != nil {
+		t.Fatal(err)
+	}
+	list, err := store.CreateGroceryList(models.GroceryList{HouseholdId: household.Id, Name: "Party"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := createGroceryItem(t, store, models.GroceryItem{HouseholdId: household.Id, ListId: list.Id, Name: "Chips"})
+	createGroceryItem(t, store, models.GroceryItem{HouseholdId: kept.Id, Name: "Bread"})
+	if err := store.SaveCategoryOverride(models.CategoryOverride{HouseholdId: household.Id, ItemName: "tofu", Category: "Meat"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveStoreRule(models.StoreRule{HouseholdId: household.Id, Category: "Dairy", Store: "Aldi"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RecordStoreChoice(household.Id, "milk", "Aldi"); err != nil {
+		t.Fatal(err)
+	}
+	job, err := store.CreateRecipeImportJob(models.RecipeImportJob{HouseholdId: household.Id, ListId: list.Id, Url: "https://example.com/soup"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipe, err := store.CreateSavedRecipe(models.SavedRecipe{HouseholdId: household.Id, Title: "Soup", Ingredients: []models.RecipeIngredient{{Line: "1 leek", Name: "leek", Quantity: 1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.DeleteHousehold(household.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = store.GetHousehold(household.Id)
+	expectNotFound(t, "GetHousehold", err)
+	_, err = store.GetGroceryList(household.Id)
+	expectNotFound(t, "default list", err)
+	_, err = store.GetGroceryList(list.Id)
+	expectNotFound(t, "other list", err)
+	_, err = store.GetGroceryItem(item.Id)
+	expectNotFound(t, "grocery item", err)
+	_, err = store.GetRecipeImportJob(job.Id)
+	expectNotFound(t, "recipe import job", err)
+	_, err = store.GetSavedRecipe(recipe.Id)
+	expectNotFound(t, "saved recipe", err)
+
+	if _, err := store.GetUser(user.Id); err != nil {
+		t.Errorf("users outlive their households: %v", err)
+	}
+	households, err := store.GetUserHouseholds(user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(households) != 0 {
+		t.Errorf("GetUserHouseholds: got %+v", households)
+	}
+
+	overrides, err := store.ListCategoryOverrides(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := store.ListStoreRules(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	choices, err := store.ListStoreHistory(household.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overrides)+len(rules)+len(choices) != 0 {
+		t.Errorf("preferences left behind: %+v %+v %+v", overrides, rules, choices)
+	}
+
+	items, err := store.ListGroceryItemsByHousehold(kept.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectStrings(t, "other household's items", itemNames(items), "Bread")
+}
@@ -0,0 +1,78 @@
+package routes
+
+import (
+	"api/models"
+	"api/providers"
+	"api/proxy"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetCategoryOverrides(c *gin.Context) {
+	householdId := c.Param("householdId")
+
+	overrides, err := providers.GetCategoryOverrides(h.store, householdId)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, overrides)
+}
+
+func (h *Handler) SaveCategoryOverride(c *gin.Context) {
+	var override models.CategoryOverride
+
+	if err := c.ShouldBindJSON(&override); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	override.HouseholdId = c.Param("householdId")
+
+	if err := override.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	saved, err := providers.SaveCategoryOverride(h.store, override)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+func (h *Handler) DeleteCategoryOverride(c *gin.Context) {
+	householdId := c.Param("householdId")
+	itemName := c.Param("itemName")
+
+	err := providers.DeleteCategoryOverride(h.store, householdId, itemName)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
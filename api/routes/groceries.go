@@ -3,40 +3,114 @@ package routes
 import (
 	"api/models"
 	"api/providers"
+	"api/proxy"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
-func GetGroceries(c *gin.Context) {
+// GetGroceries returns the household's default list, the list chosen with
+// ?listId=, or every list of the household with ?lists=all
+func (h *Handler) GetGroceries(c *gin.Context) {
 	householdId := c.Param("householdId")
 
-	groceryItems, err := providers.GetGroceryItems(householdId)
+	if c.Query("lists") == "all" {
+		groceryLists, err := providers.GetGroceryLists(h.store, householdId)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, models.GroceryListsResponse{Lists: groceryLists})
+		return
+	}
+
+	groceryList, err := providers.GetGroceryList(h.store, householdId, c.Query("listId"))
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
 
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if groceryItems == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No grocery items found"})
+	c.IndentedJSON(http.StatusOK, groceryList)
+}
+
+func (h *Handler) SaveLayout(c *gin.Context) {
+	householdId := c.Param("householdId")
+	var request models.SaveLayoutRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	layout := make([]models.LayoutBlock, len(groceryItems))
-	for i, item := range groceryItems {
-		layout[i] = models.LayoutBlock{Type: models.GroceryItemId, Value: item.Id}
+	listId := c.Query("listId")
+	layout, err := providers.SaveLayout(h.store, householdId, listId, request.Layout)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
 	}
 
-	groceryList := models.GroceryList{
-		Items:  groceryItems,
-		Layout: layout,
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
-	c.IndentedJSON(http.StatusOK, groceryList)
+	h.respondWithGroceryList(c, householdId, listId, layout)
 }
 
-func CreateGroceryItem(c *gin.Context) {
+func (h *Handler) MoveGroceryItem(c *gin.Context) {
+	householdId := c.Param("householdId")
+	var request models.MoveGroceryItemRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	listId := c.Query("listId")
+	layout, err := providers.MoveGroceryItem(h.store, householdId, listId, request)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.respondWithGroceryList(c, householdId, listId, layout)
+}
+
+// respondWithGroceryList sends the same shape as GetGroceries after a layout change
+func (h *Handler) respondWithGroceryList(c *gin.Context, householdId string, listId string, layout []models.LayoutBlock) {
+	groceryList, err := providers.GetGroceryList(h.store, householdId, listId)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	groceryList.Layout = layout
+	c.JSON(http.StatusOK, groceryList)
+}
+
+func (h *Handler) CreateGroceryItem(c *gin.Context) {
 	var groceryItem models.GroceryItem
 
 	if err := c.ShouldBindJSON(&groceryItem); err != nil {
@@ -46,7 +120,12 @@ func CreateGroceryItem(c *gin.Context) {
 
 	groceryItem.GenerateID()
 
-	err := providers.CreateGroceryItem(groceryItem)
+	err := providers.CreateGroceryItem(h.store, groceryItem)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -56,7 +135,7 @@ func CreateGroceryItem(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{})
 }
 
-func UpdateGroceryItem(c *gin.Context) {
+func (h *Handler) UpdateGroceryItem(c *gin.Context) {
 	var groceryItem models.GroceryItem
 
 	if err := c.ShouldBindJSON(&groceryItem); err != nil {
@@ -64,7 +143,7 @@ func UpdateGroceryItem(c *gin.Context) {
 		return
 	}
 
-	err := providers.UpdateGroceryItem(groceryItem)
+	err := providers.UpdateGroceryItem(h.store, groceryItem)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -74,10 +153,39 @@ func UpdateGroceryItem(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{})
 }
 
-func DeleteGroceryItem(c *gin.Context) {
+func (h *Handler) PatchGroceryItem(c *gin.Context) {
+	groceryItemId := c.Param("id")
+	var patch models.GroceryItemPatch
+
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := patch.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	groceryItem, err := providers.PatchGroceryItem(h.store, groceryItemId, patch)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, groceryItem)
+}
+
+func (h *Handler) DeleteGroceryItem(c *gin.Context) {
 	householdId := c.Param("householdId")
 	groceryItemId := c.Param("id")
-	err := providers.DeleteGroceryItem(householdId, groceryItemId)
+	err := providers.DeleteGroceryItem(h.store, householdId, groceryItemId)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -87,7 +195,7 @@ func DeleteGroceryItem(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{})
 }
 
-func BatchDeleteGroceryItems(c *gin.Context) {
+func (h *Handler) BatchDeleteGroceryItems(c *gin.Context) {
 	var request models.BatchDeleteGroceryItemsRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -95,7 +203,7 @@ func BatchDeleteGroceryItems(c *gin.Context) {
 		return
 	}
 
-	providers.BatchDeleteGroceryItems(request.ItemsToDelete)
+	providers.BatchDeleteGroceryItems(h.store, request.ItemsToDelete)
 
 	c.JSON(http.StatusOK, gin.H{})
 }
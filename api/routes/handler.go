@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"api/jobs"
+	"api/prices"
+	"api/proxy"
+)
+
+// Handler holds the dependencies shared by every route
+type Handler struct {
+	store          proxy.Store
+	priceScheduler *prices.Scheduler
+	recipeImports  *jobs.RecipeImportQueue
+}
+
+func NewHandler(store proxy.Store, priceScheduler *prices.Scheduler, recipeImports *jobs.RecipeImportQueue) *Handler {
+	return &Handler{store: store, priceScheduler: priceScheduler, recipeImports: recipeImports}
+}
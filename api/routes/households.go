@@ -1,19 +1,70 @@
 package routes
 
 import (
+	"api/models"
 	"api/providers"
+	"api/proxy"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
-func CreateHousehold(c *gin.Context) {
-	household := providers.CreateHousehold()
+func (h *Handler) CreateHousehold(c *gin.Context) {
+	household, err := providers.CreateHousehold(h.store)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, *household)
 }
 
-func JoinHousehold(c *gin.Context) {
+func (h *Handler) GetHousehold(c *gin.Context) {
+	household, err := providers.GetHousehold(h.store, c.Param("householdId"))
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, household)
+}
+
+func (h *Handler) SetMeasurementSystem(c *gin.Context) {
+	var request models.MeasurementSystemRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	household, err := providers.SetMeasurementSystem(h.store, c.Param("householdId"), request.MeasurementSystem)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, household)
+}
+
+func (h *Handler) JoinHousehold(c *gin.Context) {
 	householdId := c.Param("householdId")
 	userId := c.Param("userId")
 
@@ -31,7 +82,7 @@ func JoinHousehold(c *gin.Context) {
 		return
 	}
 
-	user, err := providers.GetOrCreateUser(userId)
+	user, err := providers.GetOrCreateUser(h.store, userId)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
@@ -39,7 +90,7 @@ func JoinHousehold(c *gin.Context) {
 		return
 	}
 
-	err = providers.JoinHousehold(user.Id, householdId)
+	err = providers.JoinHousehold(h.store, user.Id, householdId)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
@@ -50,7 +101,7 @@ func JoinHousehold(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{})
 }
 
-func LeaveHousehold(c *gin.Context) {
+func (h *Handler) LeaveHousehold(c *gin.Context) {
 	householdId := c.Param("householdId")
 	userId := c.Param("userId")
 
@@ -68,7 +119,7 @@ func LeaveHousehold(c *gin.Context) {
 		return
 	}
 
-	err := providers.LeaveHousehold(userId, householdId)
+	err := providers.LeaveHousehold(h.store, userId, householdId)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
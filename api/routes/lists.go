@@ -0,0 +1,89 @@
+package routes
+
+import (
+	"api/models"
+	"api/providers"
+	"api/proxy"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) CreateGroceryList(c *gin.Context) {
+	householdId := c.Param("householdId")
+	var request models.GroceryListRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	groceryList, err := providers.CreateGroceryList(h.store, householdId, request.Name)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, groceryList)
+}
+
+func (h *Handler) RenameGroceryList(c *gin.Context) {
+	householdId := c.Param("householdId")
+	listId := c.Param("listId")
+	var request models.GroceryListRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	groceryList, err := providers.RenameGroceryList(h.store, householdId, listId, request.Name)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, groceryList)
+}
+
+func (h *Handler) DeleteGroceryList(c *gin.Context) {
+	householdId := c.Param("householdId")
+	listId := c.Param("listId")
+
+	err := providers.DeleteGroceryList(h.store, householdId, listId)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, providers.ErrDeleteDefaultGroceryList) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
@@ -2,21 +2,19 @@ package routes
 
 import (
 	"api/models"
-	"api/parsing"
 	"api/providers"
+	"api/proxy"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strconv"
-	"strings"
-	"sync"
-	"unicode"
 
 	"github.com/gin-gonic/gin"
 )
 
-func GroceryMagic(c *gin.Context) {
+func (h *Handler) GroceryMagic(c *gin.Context) {
 	var request models.GroceryMagicRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -24,64 +22,130 @@ func GroceryMagic(c *gin.Context) {
 		return
 	}
 
+	list, err := providers.FindGroceryList(h.store, request.HouseholdId, request.GroceryList.Id)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	var taskIds []string
 	var groceryItems []models.GroceryItem
-	layoutBlockMap := make(map[models.StorePreference][]models.LayoutBlock)
-
-	var wg sync.WaitGroup
+	var recipeUrls []string
+	var recipeItems []models.GroceryItem
 
 	for _, item := range request.GroceryList.Items {
 		recipeUrl, isRecipeUrl := parseUrl(item.Name)
 
 		if isRecipeUrl {
-			wg.Add(1)
-			providers.DeleteGroceryItem(item.HouseholdId, item.Id)
-			go func() {
-				defer wg.Done()
-				recipeGroceryItems, extractedLayoutBlockMap := extractAndCreateGroceryItemsFromRecipeUrl(recipeUrl, request.HouseholdId, groceryItems, request.PreferredStores)
-
-				groceryItems = append(groceryItems, recipeGroceryItems...)
-
-				for key, value := range extractedLayoutBlockMap {
-					layoutBlockMap[key] = append(layoutBlockMap[key], value...)
-				}
-			}()
+			recipeUrls = append(recipeUrls, recipeUrl)
+			recipeItems = append(recipeItems, item)
 			continue
 		}
 
-		storePreference := getStorePreferenceForItem(item)
-
-		layoutBlockMap[storePreference] = append(layoutBlockMap[storePreference], models.LayoutBlock{
-			Value: item.Id,
-			Type:  models.GroceryItemId,
+		groceryItems = append(groceryItems, models.GroceryItem{
+			Id:            item.Id,
+			Name:          item.Name,
+			Kind:          item.Kind,
+			HouseholdId:   item.HouseholdId,
+			ListId:        list.Id,
+			Category:      item.Category,
+			StoreOverride: item.StoreOverride,
+			Checked:       item.Checked,
 		})
 
+		if item.Kind == models.TaskKind {
+			taskIds = append(taskIds, item.Id)
+		}
+	}
+
+	var recipeImport *providers.RecipeImport
+
+	recipeItemIds := make([]string, len(recipeItems))
+	for i, item := range recipeItems {
+		recipeItemIds[i] = item.Id
+	}
+
+	if request.Async {
+		recipeImport, err = providers.QueueRecipeImports(h.store, request.HouseholdId, list.Id, recipeUrls, recipeItemIds, request.Servings)
+		for range recipeUrls {
+			h.recipeImports.Notify()
+		}
+	} else {
+		recipeImport, err = providers.ImportRecipes(h.store, request.HouseholdId, list.Id, recipeUrls, recipeItemIds, request.Servings)
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The url item is only replaced by its ingredients once they are on the
+	// list, and ImportRecipes removed it in the same transaction. Anything
+	// else stays for the user to retry or remove. Queued items are removed by
+	// their job.
+	for i, result := range recipeImport.Results {
+		if result.Status == models.RecipeImported {
+			continue
+		}
+
+		item := recipeItems[i]
 		groceryItems = append(groceryItems, models.GroceryItem{
 			Id:          item.Id,
 			Name:        item.Name,
 			Kind:        item.Kind,
 			HouseholdId: item.HouseholdId,
+			ListId:      list.Id,
 			Checked:     item.Checked,
 		})
+	}
 
-		if item.Kind == models.TaskKind {
-			taskIds = append(taskIds, item.Id)
+	groceryItems = append(groceryItems, recipeImport.Created...)
+
+	// Items that recipes added quantities to are already on the list, though
+	// not necessarily in the copy of it the client sent
+	for _, updatedItem := range recipeImport.Updated {
+		replaced := false
+		for i := range groceryItems {
+			if groceryItems[i].Id == updatedItem.Id {
+				groceryItems[i] = updatedItem
+				replaced = true
+			}
 		}
+		if !replaced {
+			groceryItems = append(groceryItems, updatedItem)
+		}
+	}
+
+	stores, err := providers.AssignStores(h.store, request.HouseholdId, request.PreferredStores, groceryItems)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
-	wg.Wait()
-	var layout []models.LayoutBlock
-	for key, blocks := range layoutBlockMap {
-		layout = append(layout, models.LayoutBlock{Value: string(key), Type: models.Text})
-		layout = append(layout, blocks...)
+	layout := providers.LayoutByStore(groceryItems, stores, request.PreferredStores)
+	layout, err = providers.SaveLayout(h.store, request.HouseholdId, list.Id, layout)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
 	groceryList := models.GroceryList{
-		Items:  groceryItems,
-		Layout: layout,
+		Id:          list.Id,
+		HouseholdId: list.HouseholdId,
+		Name:        list.Name,
+		Items:       groceryItems,
+		Layout:      layout,
 	}
 
-	schedule, scheduleFetchError := providers.GetSchedule(taskIds)
+	schedule, scheduleFetchError := providers.GetSchedule(h.store, taskIds)
 
 	if scheduleFetchError != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": scheduleFetchError.Error()})
@@ -89,36 +153,14 @@ func GroceryMagic(c *gin.Context) {
 	}
 
 	response := models.GroceryMagicResponse{
-		GroceryList: groceryList,
-		Schedule:    schedule,
+		GroceryList:   groceryList,
+		Schedule:      schedule,
+		RecipeImports: recipeImport.Results,
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
-func getStorePreferenceForItem(item models.GroceryItem) models.StorePreference {
-	return models.Unknown
-}
-
-func getStorePreferenceForItemName(itemName string) models.StorePreference {
-	return models.Unknown
-}
-
-func removeEmojis(s string) string {
-	return strings.Map(func(r rune) rune {
-		if r > unicode.MaxASCII {
-			return -1
-		}
-		return r
-	}, s)
-}
-
-func parseItemName(itemName string) string {
-	parsedItemName := removeEmojis(itemName)
-	parsedItemName = strings.TrimSpace(parsedItemName)
-	return strings.ToLower(parsedItemName)
-}
-
 func parseUrl(itemName string) (string, bool) {
 	u, err := url.ParseRequestURI(itemName)
 	if err != nil {
@@ -128,55 +170,6 @@ func parseUrl(itemName string) (string, bool) {
 	return u.String(), true
 }
 
-func extractAndCreateGroceryItemsFromRecipeUrl(recipeUrl string, householdId string, existingGroceryItems []models.GroceryItem, preferredStores []models.StorePreference) ([]models.GroceryItem, map[models.StorePreference][]models.LayoutBlock) {
-	recipe, _ := parsing.NewFromURL(recipeUrl)
-	ingredients := recipe.IngredientList().Ingredients
-
-	var groceryItems []models.GroceryItem = make([]models.GroceryItem, len(ingredients))
-	layoutBlockMap := make(map[models.StorePreference][]models.LayoutBlock)
-
-	for i, ingredient := range ingredients {
-		if isIngredientAlreadyInGroceryList(ingredient, existingGroceryItems) {
-			continue
-		}
-
-		storePreference := getCheapestStoreForItemOrStorePreference(ingredient.Name, preferredStores)
-
-		groceryItem := models.GroceryItem{
-			HouseholdId:   householdId,
-			Name:          ingredient.Name,
-			Checked:       false,
-			StoreOverride: "",
-		}
-
-		groceryItem.GenerateID()
-
-		providers.CreateGroceryItem(groceryItem)
-		groceryItems[i] = groceryItem
-
-		layoutBlockMap[storePreference] = append(layoutBlockMap[storePreference], models.LayoutBlock{
-			Value: groceryItem.Id,
-			Type:  models.GroceryItemId,
-		})
-	}
-
-	return groceryItems, layoutBlockMap
-}
-
-func isIngredientAlreadyInGroceryList(ingredient parsing.Ingredient, groceryItems []models.GroceryItem) bool {
-	for _, existingGroceryItem := range groceryItems {
-		if ingredient.Name == existingGroceryItem.Name {
-			return true
-		}
-	}
-
-	return false
-}
-
-func getCheapestStoreForItemOrStorePreference(itemName string, preferredStores []models.StorePreference) models.StorePreference {
-	return models.Unknown
-}
-
 func extractNumber(s string) (float64, error) {
 	// Regular expression to match the first numeric value in the string
 	re := regexp.MustCompile(`[-+]?[0-9]*\.?[0-9]+`)
@@ -0,0 +1,67 @@
+package routes
+
+import (
+	"api/models"
+	"api/prices"
+	"api/providers"
+	"api/proxy"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) LookupPrices(c *gin.Context) {
+	itemName := c.Query("name")
+
+	if itemName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name must not be empty"})
+		return
+	}
+
+	itemData, err := providers.LookupPrices(h.store, itemName)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, itemData)
+}
+
+// ImportPrices takes a CSV body when sent as text/csv and a JSON array of
+// prices otherwise
+func (h *Handler) ImportPrices(c *gin.Context) {
+	var storePrices []models.StoreData
+	var err error
+
+	if c.ContentType() == "text/csv" {
+		storePrices, err = prices.ParseCSV(c.Request.Body, time.Now())
+	} else {
+		storePrices, err = prices.ParseJSON(c.Request.Body, time.Now())
+	}
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	imported, err := providers.ImportPrices(h.store, storePrices)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ImportPricesResponse{Imported: imported})
+}
+
+func (h *Handler) GetPriceRefreshStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.priceScheduler.Status())
+}
@@ -0,0 +1,85 @@
+package routes
+
+import (
+	"api/extract"
+	"api/models"
+	"api/providers"
+	"api/proxy"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScanReceipt takes a multipart upload with the receipt, a PDF or a photo,
+// as "file" and optionally the "store" it is from
+func (h *Handler) ScanReceipt(c *gin.Context) {
+	householdId := c.Param("householdId")
+	storeName := models.StorePreference(strings.TrimSpace(c.PostForm("store")))
+
+	name, data, err := readUpload(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scan, err := providers.ScanReceipt(h.store, householdId, storeName, name, data)
+
+	if errors.Is(err, extract.ErrOCRUnavailable) {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, extract.ErrNotPDF) || errors.Is(err, extract.ErrNotImage) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, providers.ErrNoReceiptItems) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, scan)
+}
+
+func (h *Handler) ConfirmReceipt(c *gin.Context) {
+	var request models.ConfirmReceiptRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request.HouseholdId = c.Param("householdId")
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := providers.ConfirmReceipt(h.store, request)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"api/models"
+	"api/providers"
+	"api/proxy"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueueRecipeImport responds straight away with the queued job, which can be
+// polled with GetRecipeImportJob until it is Done
+func (h *Handler) QueueRecipeImport(c *gin.Context) {
+	var request models.RecipeImportRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := providers.QueueRecipeImport(h.store, request)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.recipeImports.Notify()
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+func (h *Handler) GetRecipeImportJob(c *gin.Context) {
+	job, err := providers.GetRecipeImportJob(h.store, c.Param("jobId"))
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
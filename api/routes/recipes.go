@@ -0,0 +1,172 @@
+package routes
+
+import (
+	"api/extract"
+	"api/models"
+	"api/providers"
+	"api/proxy"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PreviewRecipeText takes the recipe as a text/plain or text/markdown body,
+// with ?servings= to scale it, or as a JSON RecipeTextRequest
+func (h *Handler) PreviewRecipeText(c *gin.Context) {
+	var request models.RecipeTextRequest
+
+	switch c.ContentType() {
+	case "text/plain", "text/markdown":
+		text, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadSize))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("recipe must be at most %d MB", maxUploadSize>>20)})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		request.Text = string(text)
+
+		if servings := c.Query("servings"); servings != "" {
+			request.Servings, err = strconv.ParseFloat(servings, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "servings must be a number"})
+				return
+			}
+		}
+	default:
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preview, err := providers.PreviewRecipeText(request.Text, request.Servings)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// maxUploadSize bounds the files, recipes and receipts, that are read into memory
+const maxUploadSize = 20 << 20
+
+// PreviewRecipePDF takes a multipart upload with the PDF as "file" and an
+// optional "servings" to scale it to
+func (h *Handler) PreviewRecipePDF(c *gin.Context) {
+	name, data, servings, err := readRecipeUpload(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preview, err := providers.PreviewRecipePDF(name, data, servings)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// PreviewRecipeImage takes a photo the same way PreviewRecipePDF takes a PDF
+func (h *Handler) PreviewRecipeImage(c *gin.Context) {
+	name, data, servings, err := readRecipeUpload(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preview, err := providers.PreviewRecipeImage(name, data, servings)
+
+	if errors.Is(err, extract.ErrOCRUnavailable) {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// readRecipeUpload reads the "file" and "servings" fields of a multipart form
+func readRecipeUpload(c *gin.Context) (name string, data []byte, servings float64, err error) {
+	if value := c.PostForm("servings"); value != "" {
+		servings, err = strconv.ParseFloat(value, 64)
+		if err != nil || servings < 0 {
+			return "", nil, 0, fmt.Errorf("servings must be a number that is not negative")
+		}
+	}
+
+	name, data, err = readUpload(c)
+	return name, data, servings, err
+}
+
+// readUpload reads the "file" field of a multipart form
+func readUpload(c *gin.Context) (name string, data []byte, err error) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return "", nil, fmt.Errorf("file must be uploaded: %w", err)
+	}
+
+	if fileHeader.Size > maxUploadSize {
+		return "", nil, fmt.Errorf("file must be at most %d MB", maxUploadSize>>20)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", nil, err
+	}
+	defer file.Close()
+
+	data, err = io.ReadAll(io.LimitReader(file, maxUploadSize))
+	return fileHeader.Filename, data, err
+}
+
+// AddRecipeIngredients adds the ingredients of a preview, as the user left
+// them, to a list
+func (h *Handler) AddRecipeIngredients(c *gin.Context) {
+	var request models.AddIngredientsRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := providers.AddRecipeIngredients(h.store, request)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
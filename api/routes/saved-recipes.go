@@ -0,0 +1,153 @@
+package routes
+
+import (
+	"api/models"
+	"api/providers"
+	"api/proxy"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListSavedRecipes searches the library when given ?q=
+func (h *Handler) ListSavedRecipes(c *gin.Context) {
+	householdId := c.Param("householdId")
+
+	recipes, err := providers.ListSavedRecipes(h.store, householdId, c.Query("q"))
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, recipes)
+}
+
+func (h *Handler) SaveRecipe(c *gin.Context) {
+	var recipe models.SavedRecipe
+
+	if err := c.ShouldBindJSON(&recipe); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recipe.HouseholdId = c.Param("householdId")
+
+	if err := recipe.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	saved, err := providers.SaveRecipe(h.store, recipe)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+func (h *Handler) GetSavedRecipe(c *gin.Context) {
+	householdId := c.Param("householdId")
+	recipeId := c.Param("recipeId")
+
+	recipe, err := providers.GetSavedRecipe(h.store, householdId, recipeId)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, recipe)
+}
+
+func (h *Handler) PatchSavedRecipe(c *gin.Context) {
+	householdId := c.Param("householdId")
+	recipeId := c.Param("recipeId")
+	var patch models.SavedRecipePatch
+
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := patch.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recipe, err := providers.UpdateSavedRecipe(h.store, householdId, recipeId, patch)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, recipe)
+}
+
+func (h *Handler) DeleteSavedRecipe(c *gin.Context) {
+	householdId := c.Param("householdId")
+	recipeId := c.Param("recipeId")
+
+	err := providers.DeleteSavedRecipe(h.store, householdId, recipeId)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// AddSavedRecipe adds the recipe's ingredients to the list in the body, or
+// the household's default list
+func (h *Handler) AddSavedRecipe(c *gin.Context) {
+	householdId := c.Param("householdId")
+	recipeId := c.Param("recipeId")
+	var request models.AddSavedRecipeRequest
+
+	// An empty body adds the recipe as saved to the default list
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := providers.AddSavedRecipe(h.store, householdId, recipeId, request)
+
+	if errors.Is(err, proxy.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func ScheduleTask(c *gin.Context) {
+func (h *Handler) ScheduleTask(c *gin.Context) {
 	var request models.ScheduleTaskRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -16,7 +16,7 @@ func ScheduleTask(c *gin.Context) {
 		return
 	}
 
-	err := providers.CreateTaskSchedule(request)
+	err := providers.CreateTaskSchedule(h.store, request)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
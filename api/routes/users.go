@@ -2,24 +2,24 @@ package routes
 
 import (
 	"api/providers"
-	db "api/proxy/sqlite"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
-func CreateUser(c *gin.Context) {
-	user := providers.CreateUser()
+func (h *Handler) CreateUser(c *gin.Context) {
+	user, err := providers.CreateUser(h.store)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, user)
 }
 
-func GetUser(c *gin.Context) {
+func (h *Handler) GetUser(c *gin.Context) {
 	id := c.Param("id")
-	database, _ := db.NewDB()
-	defer database.Close()
-
-	user, err := providers.GetOrCreateUser(id)
+	user, err := providers.GetOrCreateUser(h.store, id)
 
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err})
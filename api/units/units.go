@@ -0,0 +1,193 @@
+// Package units converts recipe quantities between the canonical units that
+// parsing.NormalizeMeasureName produces
+package units
+
+import (
+	"api/models"
+	"math"
+
+	"github.com/jinzhu/inflection"
+)
+
+const (
+	Teaspoon   = "tsp"
+	Tablespoon = "tbl"
+	Cup        = "cup"
+	Pint       = "pint"
+	Quart      = "quart"
+	Milliliter = "milliliter"
+	Liter      = "liter"
+	Ounce      = "ounce"
+	Pound      = "pound"
+	Gram       = "gram"
+	Kilogram   = "kilogram"
+)
+
+// millilitersPerUnit and gramsPerUnit cover every volume and weight unit.
+// Units in neither map ("can", or "" for counted ingredients) can only be
+// combined with themselves.
+var millilitersPerUnit = map[string]float64{
+	Milliliter: 1,
+	Liter:      1000,
+	Teaspoon:   4.92892,
+	Tablespoon: 14.7868,
+	Cup:        236.588,
+	Pint:       473.176,
+	Quart:      946.353,
+}
+
+var gramsPerUnit = map[string]float64{
+	Gram:     1,
+	Kilogram: 1000,
+	Ounce:    28.3495,
+	Pound:    453.592,
+}
+
+// metricUnits and imperialUnits say which system a unit belongs to. Spoons
+// are in neither since metric recipes measure with them too.
+var metricUnits = map[string]bool{Milliliter: true, Liter: true, Gram: true, Kilogram: true}
+
+var imperialUnits = map[string]bool{Cup: true, Pint: true, Quart: true, Ounce: true, Pound: true}
+
+func IsVolume(unit string) bool {
+	_, ok := millilitersPerUnit[unit]
+	return ok
+}
+
+func IsWeight(unit string) bool {
+	_, ok := gramsPerUnit[unit]
+	return ok
+}
+
+// Convert converts an amount between two units. Volume and weight are
+// converted into each other through the ingredient's density, so that only
+// works for ingredients listed in densities.
+func Convert(ingredientName string, amount float64, from string, to string) (float64, bool) {
+	if from == to {
+		return amount, true
+	}
+
+	fromMl, fromIsVolume := millilitersPerUnit[from]
+	fromGrams, fromIsWeight := gramsPerUnit[from]
+	toMl, toIsVolume := millilitersPerUnit[to]
+	toGrams, toIsWeight := gramsPerUnit[to]
+
+	switch {
+	case fromIsVolume && toIsVolume:
+		return amount * fromMl / toMl, true
+	case fromIsWeight && toIsWeight:
+		return amount * fromGrams / toGrams, true
+	}
+
+	gramsPerCup, ok := DensityOf(ingredientName)
+	if !ok {
+		return 0, false
+	}
+	gramsPerMl := gramsPerCup / millilitersPerUnit[Cup]
+
+	switch {
+	case fromIsVolume && toIsWeight:
+		return amount * fromMl * gramsPerMl / toGrams, true
+	case fromIsWeight && toIsVolume:
+		return amount * fromGrams / gramsPerMl / toMl, true
+	}
+
+	return 0, false
+}
+
+// DensityOf returns the grams per cup of an ingredient, trying both the
+// singular and plural spellings since the table has a mix of both
+func DensityOf(ingredientName string) (float64, bool) {
+	if density, ok := densities[ingredientName]; ok {
+		return density, true
+	}
+	if density, ok := densities[inflection.Plural(ingredientName)]; ok {
+		return density, true
+	}
+	if density, ok := densities[inflection.Singular(ingredientName)]; ok {
+		return density, true
+	}
+
+	return 0, false
+}
+
+// ToSystem rewrites an amount in the household's measurement system, picking
+// the unit that reads best for its size, e.g. 1500 grams is 1.5 kilograms and
+// 20 ounces is 1.25 pounds. Volumes stay volumes and weights stay weights.
+// Amounts already in the system, spoons, and units with no system such as
+// cans are left alone.
+func ToSystem(amount float64, unit string, system models.MeasurementSystem) (float64, string) {
+	switch system {
+	case models.MetricSystem:
+		if metricUnits[unit] || !imperialUnits[unit] {
+			return amount, unit
+		}
+	case models.ImperialSystem:
+		if imperialUnits[unit] || !metricUnits[unit] {
+			return amount, unit
+		}
+	default:
+		return amount, unit
+	}
+
+	if ml, ok := millilitersPerUnit[unit]; ok {
+		ml *= amount
+		to := volumeUnit(ml, system)
+		return Round(ml/millilitersPerUnit[to], to), to
+	}
+
+	grams := amount * gramsPerUnit[unit]
+	to := weightUnit(grams, system)
+	return Round(grams/gramsPerUnit[to], to), to
+}
+
+func volumeUnit(ml float64, system models.MeasurementSystem) string {
+	if system == models.MetricSystem {
+		if ml >= millilitersPerUnit[Liter] {
+			return Liter
+		}
+		return Milliliter
+	}
+
+	switch {
+	case ml < millilitersPerUnit[Tablespoon]:
+		return Teaspoon
+	case ml < millilitersPerUnit[Cup]/4:
+		return Tablespoon
+	case ml < millilitersPerUnit[Quart]:
+		return Cup
+	}
+	return Quart
+}
+
+func weightUnit(grams float64, system models.MeasurementSystem) string {
+	if system == models.MetricSystem {
+		if grams >= gramsPerUnit[Kilogram] {
+			return Kilogram
+		}
+		return Gram
+	}
+
+	if grams >= gramsPerUnit[Pound] {
+		return Pound
+	}
+	return Ounce
+}
+
+// Round keeps converted amounts readable: whole milliliters and grams, and
+// two decimals for everything else, e.g. 5.99 cups not 5.989375... Amounts too
+// small for that keep their first significant digit, so 0.4 grams isn't 0.
+func Round(amount float64, unit string) float64 {
+	var rounded float64
+	if unit == Milliliter || unit == Gram {
+		rounded = math.Round(amount)
+	} else {
+		rounded = math.Round(amount*100) / 100
+	}
+	if rounded != 0 || amount == 0 {
+		return rounded
+	}
+
+	scale := math.Pow(10, -math.Floor(math.Log10(math.Abs(amount))))
+	return math.Round(amount*scale) / scale
+}
@@ -0,0 +1,156 @@
+package units
+
+import (
+	"api/models"
+	"math"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		ingredient string
+		amount     float64
+		from, to   string
+		want       float64
+	}{
+		// volumes
+		{"milk", 1, Cup, Tablespoon, 16},
+		{"milk", 3, Teaspoon, Tablespoon, 1},
+		{"milk", 1.5, Liter, Milliliter, 1500},
+		{"milk", 2, Pint, Quart, 1},
+		// weights
+		{"flour", 1, Pound, Ounce, 16},
+		{"flour", 2.5, Kilogram, Gram, 2500},
+		{"flour", 100, Gram, Ounce, 3.5274},
+		// volume to weight and back through the density, whichever of the
+		// singular and plural the table has
+		{"flour", 1, Cup, Gram, 113.7},
+		{"flours", 2, Cup, Gram, 227.4},
+		{"walnut", 1, Cup, Gram, 95},
+		{"walnuts", 1, Cup, Gram, 95},
+		{"sugar", 205.8, Gram, Cup, 1},
+		{"butter", 1, Pound, Cup, 1.9982},
+		{"honey", 1, Tablespoon, Ounce, 0.1302},
+		// the same unit is left as it is, even one that can't be converted
+		{"tomatoes", 2, "can", "can", 2},
+		{"eggs", 3, "", "", 3},
+	}
+
+	for _, tt := range tests {
+		got, ok := Convert(tt.ingredient, tt.amount, tt.from, tt.to)
+		if !ok || math.Abs(got-tt.want) > 0.0001 {
+			t.Errorf("Convert(%q, %v, %q, %q) = %v, %v, want %v", tt.ingredient, tt.amount, tt.from, tt.to, got, ok, tt.want)
+		}
+	}
+}
+
+func TestConvertUnconvertible(t *testing.T) {
+	tests := []struct {
+		ingredient string
+		from, to   string
+	}{
+		// no density for the ingredient
+		{"unobtainium", Cup, Gram},
+		{"unobtainium", Ounce, Milliliter},
+		// units that are neither volumes nor weights
+		{"tomatoes", "can", Cup},
+		{"tomatoes", Gram, "can"},
+		{"eggs", "", Gram},
+		{"eggs", Cup, ""},
+		{"garlic", "clove", "head"},
+	}
+
+	for _, tt := range tests {
+		if got, ok := Convert(tt.ingredient, 1, tt.from, tt.to); ok {
+			t.Errorf("Convert(%q, 1, %q, %q) = %v, want it not converted", tt.ingredient, tt.from, tt.to, got)
+		}
+	}
+}
+
+func TestDensityOf(t *testing.T) {
+	tests := []struct {
+		ingredient string
+		want       float64
+		ok         bool
+	}{
+		{"butter", 227, true},
+		{"pecan", 107, true},
+		{"flours", 113.7, true},
+		// the name as given wins over its other spelling
+		{"onion", 152.6, true},
+		{"onions", 176.5, true},
+		{"unobtainium", 0, false},
+	}
+
+	for _, tt := range tests {
+		if got, ok := DensityOf(tt.ingredient); got != tt.want || ok != tt.ok {
+			t.Errorf("DensityOf(%q) = %v, %v, want %v, %v", tt.ingredient, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestToSystem(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		unit     string
+		system   models.MeasurementSystem
+		want     float64
+		wantUnit string
+	}{
+		{2, Cup, models.MetricSystem, 473, Milliliter},
+		{5, Cup, models.MetricSystem, 1.18, Liter},
+		{1, Quart, models.MetricSystem, 946, Milliliter},
+		{8, Ounce, models.MetricSystem, 227, Gram},
+		{3, Pound, models.MetricSystem, 1.36, Kilogram},
+		// already metric, spoons and units without a system stay as they are
+		{1500, Gram, models.MetricSystem, 1500, Gram},
+		{2, Tablespoon, models.MetricSystem, 2, Tablespoon},
+		{1, "can", models.MetricSystem, 1, "can"},
+		{3, "", models.MetricSystem, 3, ""},
+
+		{10, Milliliter, models.ImperialSystem, 2.03, Teaspoon},
+		{30, Milliliter, models.ImperialSystem, 2.03, Tablespoon},
+		{250, Milliliter, models.ImperialSystem, 1.06, Cup},
+		{2, Liter, models.ImperialSystem, 2.11, Quart},
+		{100, Gram, models.ImperialSystem, 3.53, Ounce},
+		{1.5, Kilogram, models.ImperialSystem, 3.31, Pound},
+		{20, Ounce, models.ImperialSystem, 20, Ounce},
+		{1, "can", models.ImperialSystem, 1, "can"},
+
+		// no system leaves everything alone
+		{2, Cup, "", 2, Cup},
+		{500, Gram, "", 500, Gram},
+	}
+
+	for _, tt := range tests {
+		got, gotUnit := ToSystem(tt.amount, tt.unit, tt.system)
+		if got != tt.want || gotUnit != tt.wantUnit {
+			t.Errorf("ToSystem(%v, %q, %q) = %v %s, want %v %s", tt.amount, tt.unit, tt.system, got, gotUnit, tt.want, tt.wantUnit)
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		amount float64
+		unit   string
+		want   float64
+	}{
+		{5.989375, Cup, 5.99},
+		{12.6, Gram, 13},
+		{472.5, Milliliter, 473},
+		{1.25, Pound, 1.25},
+		// small amounts keep a significant digit
+		{0.4, Gram, 0.4},
+		{0.45, Milliliter, 0.5},
+		{0.004, Cup, 0.004},
+		{0.0049, Teaspoon, 0.005},
+		{0, Gram, 0},
+	}
+
+	for _, tt := range tests {
+		if got := Round(tt.amount, tt.unit); got != tt.want {
+			t.Errorf("Round(%v, %q) = %v, want %v", tt.amount, tt.unit, got, tt.want)
+		}
+	}
+}
@@ -1,4 +1,3 @@
 #!/bin/bash
 (cd ./spa && npm i && npm run build)
-(cd ./api/db && sh generate.sh)
 (cd ./api && go build -o dist/bootstrap . && ./dist/bootstrap)